@@ -33,6 +33,7 @@ func AddCommands(cmd *cobra.Command, dockerCli *command.DockerCli) {
 		// container
 		container.NewContainerCommand(dockerCli),
 		container.NewRunCommand(dockerCli),
+		container.NewReplayCommand(dockerCli),
 
 		// image
 		image.NewImageCommand(dockerCli),
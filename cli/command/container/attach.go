@@ -1,14 +1,19 @@
 package container
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"net/http/httputil"
+	"strconv"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -18,6 +23,8 @@ type attachOptions struct {
 	noStdin    bool
 	proxy      bool
 	detachKeys string
+	record     string
+	logs       string
 
 	container string
 }
@@ -40,6 +47,9 @@ func NewAttachCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVar(&opts.noStdin, "no-stdin", false, "Do not attach STDIN")
 	flags.BoolVar(&opts.proxy, "sig-proxy", true, "Proxy all received signals to the process")
 	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.StringVar(&opts.record, "record", "", "Record the session to a file in asciicast v2 format")
+	flags.StringVar(&opts.logs, "logs", "", "Replay the output of the container before attaching, or only its last N lines with --logs=N (the \"=\" is required)")
+	flags.Lookup("logs").NoOptDefVal = "all"
 	return cmd
 }
 
@@ -47,6 +57,11 @@ func runAttach(dockerCli *command.DockerCli, opts *attachOptions) error {
 	ctx := context.Background()
 	client := dockerCli.Client()
 
+	tail, err := parseLogsTail(opts.logs)
+	if err != nil {
+		return err
+	}
+
 	c, err := client.ContainerInspect(ctx, opts.container)
 	if err != nil {
 		return err
@@ -74,12 +89,41 @@ func runAttach(dockerCli *command.DockerCli, opts *attachOptions) error {
 		Stdout:     true,
 		Stderr:     true,
 		DetachKeys: dockerCli.ConfigFile().DetachKeys,
+		Logs:       opts.logs != "",
 	}
 
-	var in io.ReadCloser
+	var (
+		in       io.ReadCloser
+		out      io.Writer = dockerCli.Out()
+		cerr     io.Writer = dockerCli.Err()
+		recorder *sessionRecorder
+	)
 	if options.Stdin {
 		in = dockerCli.In()
 	}
+	if opts.record != "" {
+		if recorder, err = newSessionRecorder(dockerCli, opts.record, "docker attach "+opts.container); err != nil {
+			return err
+		}
+		defer recorder.Close()
+		in, out, cerr = recorder.input(in), recorder.output(out), recorder.output(cerr)
+	}
+	if tail >= 0 {
+		// The attach API replays all the buffered output, so count it to
+		// drop the lines before the last ones. The logs are read a second
+		// time for this, before attaching: lines written in between are
+		// counted but not skipped, so a busy container may show a few more
+		// lines than asked. The skipping stops at the end of the replay, so
+		// that a count larger than the replay never drops live output.
+		buffered, err := countLogLines(ctx, dockerCli, opts.container, c.Config.Tty)
+		if err != nil {
+			return err
+		}
+		if buffered > tail {
+			skipper := newLineSkipper(buffered - tail)
+			out, cerr = skipper.writer(out), skipper.writer(cerr)
+		}
+	}
 
 	if opts.proxy && !c.Config.Tty {
 		sigc := ForwardAllSignals(ctx, dockerCli, opts.container)
@@ -105,13 +149,16 @@ func runAttach(dockerCli *command.DockerCli, opts *attachOptions) error {
 
 		// After the above resizing occurs, the call to MonitorTtySize below will handle resetting back
 		// to the actual size.
-		if err := MonitorTtySize(ctx, dockerCli, opts.container, false); err != nil {
+		if err := monitorTtySize(ctx, dockerCli, opts.container, false, recorder); err != nil {
 			logrus.Debugf("Error monitoring TTY size: %s", err)
 		}
 	}
-	if err := holdHijackedConnection(ctx, dockerCli, c.Config.Tty, in, dockerCli.Out(), dockerCli.Err(), resp); err != nil {
+	if err := holdHijackedConnection(ctx, dockerCli, c.Config.Tty, in, out, cerr, resp); err != nil {
 		return err
 	}
+	if err := recorder.Close(); err != nil {
+		fmt.Fprintln(dockerCli.Err(), "Error writing the recording:", err)
+	}
 
 	if errAttach != nil {
 		return errAttach
@@ -127,3 +174,112 @@ func runAttach(dockerCli *command.DockerCli, opts *attachOptions) error {
 
 	return nil
 }
+
+// parseLogsTail parses the value of the --logs flag into the number of lines
+// to replay, or -1 for all of them
+func parseLogsTail(value string) (int, error) {
+	if value == "" || value == "all" {
+		return -1, nil
+	}
+	tail, err := strconv.Atoi(value)
+	if err != nil || tail < 0 {
+		return 0, errors.Errorf("invalid value %q for --logs: must be \"all\" or a number of lines", value)
+	}
+	return tail, nil
+}
+
+// countLogLines returns the number of lines in the logs of a container at the
+// time of the call, on both its stdout and stderr
+func countLogLines(ctx context.Context, dockerCli command.Cli, container string, tty bool) (int, error) {
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, container, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read the logs of the container")
+	}
+	defer responseBody.Close()
+
+	counter := &lineCounter{}
+	if tty {
+		_, err = io.Copy(counter, responseBody)
+	} else {
+		_, err = stdcopy.StdCopy(counter, counter, responseBody)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read the logs of the container")
+	}
+	return counter.lines, nil
+}
+
+type lineCounter struct {
+	lines int
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.lines += bytes.Count(p, []byte{'\n'})
+	return len(p), nil
+}
+
+// replayIdle is the pause in the output of an attach after which the replay
+// of the buffered output is considered done
+const replayIdle = 200 * time.Millisecond
+
+// lineSkipper drops the first lines written to its writers, which share the
+// count of the lines left to drop. As the replay of an attach is written at
+// once, the lines written after a pause are live output, which is never
+// dropped: the skipping stops at the first write after a pause.
+type lineSkipper struct {
+	remaining int
+	now       func() time.Time
+	last      time.Time
+}
+
+func newLineSkipper(lines int) *lineSkipper {
+	return &lineSkipper{remaining: lines, now: time.Now}
+}
+
+// skipping records a write, and reports whether lines are still dropped
+func (s *lineSkipper) skipping() bool {
+	now := s.now()
+	if !s.last.IsZero() && now.Sub(s.last) > replayIdle {
+		s.remaining = 0
+	}
+	s.last = now
+	return s.remaining > 0
+}
+
+func (s *lineSkipper) writer(w io.Writer) io.Writer {
+	return &skipWriter{skipper: s, w: w}
+}
+
+type skipWriter struct {
+	skipper *lineSkipper
+	w       io.Writer
+}
+
+func (w *skipWriter) Write(p []byte) (int, error) {
+	data := p
+	if !w.skipper.skipping() {
+		_, err := w.w.Write(data)
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	for w.skipper.remaining > 0 && len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		data = data[end+1:]
+		w.skipper.remaining--
+	}
+	if len(data) == 0 {
+		return len(p), nil
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
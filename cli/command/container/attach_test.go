@@ -0,0 +1,106 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestParseLogsTail(t *testing.T) {
+	for value, expected := range map[string]int{"": -1, "all": -1, "0": 0, "25": 25} {
+		tail, err := parseLogsTail(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, tail, value)
+	}
+	for _, value := range []string{"-1", "last", "1.5"} {
+		_, err := parseLogsTail(value)
+		assert.EqualError(t, err, `invalid value "`+value+`" for --logs: must be "all" or a number of lines`)
+	}
+}
+
+func TestCountLogLines(t *testing.T) {
+	var multiplexed bytes.Buffer
+	stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("one\ntwo\n"))
+	stdcopy.NewStdWriter(&multiplexed, stdcopy.Stderr).Write([]byte("three\n"))
+	stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("partial"))
+
+	testCases := []struct {
+		tty      bool
+		logs     string
+		expected int
+	}{
+		{tty: false, logs: multiplexed.String(), expected: 3},
+		{tty: true, logs: "one\r\ntwo\r\n$ ", expected: 2},
+	}
+	for _, tc := range testCases {
+		logs := tc.logs
+		cli := test.NewFakeCli(&fakeClient{
+			containerLogsFunc: func(container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+				assert.Equal(t, "app", container)
+				assert.True(t, options.ShowStdout)
+				assert.True(t, options.ShowStderr)
+				assert.False(t, options.Follow)
+				return ioutil.NopCloser(strings.NewReader(logs)), nil
+			},
+		}, new(bytes.Buffer))
+		lines, err := countLogLines(context.Background(), cli, "app", tc.tty)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, lines)
+	}
+}
+
+func TestLineSkipper(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	now := time.Now()
+	skipper := &lineSkipper{remaining: 3, now: func() time.Time { return now }}
+	out, cerr := skipper.writer(&stdout), skipper.writer(&stderr)
+
+	for _, write := range []struct {
+		w    io.Writer
+		data string
+	}{
+		{w: out, data: "old 1\nol"},
+		{w: out, data: "d 2\n"},
+		{w: cerr, data: "old 3\nrecent 4\n"},
+		{w: out, data: "live 5\n"},
+	} {
+		n, err := write.w.Write([]byte(write.data))
+		require.NoError(t, err)
+		assert.Equal(t, len(write.data), n)
+	}
+	assert.Equal(t, "live 5\n", stdout.String())
+	assert.Equal(t, "recent 4\n", stderr.String())
+	assert.Equal(t, 0, skipper.remaining)
+}
+
+func TestLineSkipperReplayShorterThanCount(t *testing.T) {
+	var stdout bytes.Buffer
+	now := time.Now()
+	skipper := &lineSkipper{remaining: 5, now: func() time.Time { return now }}
+	out := skipper.writer(&stdout)
+
+	// the replay only has 2 lines, both dropped
+	_, err := out.Write([]byte("old 1\nold 2\n"))
+	require.NoError(t, err)
+
+	// the live output comes after a pause, and is never dropped
+	now = now.Add(time.Second)
+	_, err = out.Write([]byte("live 3\n"))
+	require.NoError(t, err)
+	now = now.Add(time.Millisecond)
+	_, err = out.Write([]byte("live 4\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "live 3\nlive 4\n", stdout.String())
+	assert.Equal(t, 0, skipper.remaining)
+}
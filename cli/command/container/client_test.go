@@ -0,0 +1,88 @@
+package container
+
+import (
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"golang.org/x/net/context"
+)
+
+type fakeClient struct {
+	client.Client
+	containerListFunc     func(types.ContainerListOptions) ([]types.Container, error)
+	containerCreateFunc   func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error)
+	imageCreateFunc       func(string, types.ImageCreateOptions) (io.ReadCloser, error)
+	containerLogsFunc     func(string, types.ContainerLogsOptions) (io.ReadCloser, error)
+	containerStatPathFunc func(string, string) (types.ContainerPathStat, error)
+	copyFromContainerFunc func(string, string) (io.ReadCloser, types.ContainerPathStat, error)
+	copyToContainerFunc   func(string, string, io.Reader, types.CopyToContainerOptions) error
+	containerInspectFunc  func(string) (types.ContainerJSON, error)
+	eventsFunc            func(types.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+func (cli *fakeClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if cli.containerListFunc != nil {
+		return cli.containerListFunc(options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error) {
+	if cli.containerCreateFunc != nil {
+		return cli.containerCreateFunc(config, hostConfig, networkingConfig, containerName)
+	}
+	return container.ContainerCreateCreatedBody{}, nil
+}
+
+func (cli *fakeClient) ImageCreate(ctx context.Context, parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error) {
+	if cli.imageCreateFunc != nil {
+		return cli.imageCreateFunc(parentReference, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	if cli.containerLogsFunc != nil {
+		return cli.containerLogsFunc(container, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error) {
+	if cli.containerStatPathFunc != nil {
+		return cli.containerStatPathFunc(container, path)
+	}
+	return types.ContainerPathStat{}, nil
+}
+
+func (cli *fakeClient) CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	if cli.copyFromContainerFunc != nil {
+		return cli.copyFromContainerFunc(container, srcPath)
+	}
+	return nil, types.ContainerPathStat{}, nil
+}
+
+func (cli *fakeClient) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	if cli.copyToContainerFunc != nil {
+		return cli.copyToContainerFunc(container, path, content, options)
+	}
+	return nil
+}
+
+func (cli *fakeClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	if cli.containerInspectFunc != nil {
+		return cli.containerInspectFunc(container)
+	}
+	return types.ContainerJSON{}, nil
+}
+
+func (cli *fakeClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	if cli.eventsFunc != nil {
+		return cli.eventsFunc(options)
+	}
+	return nil, nil
+}
@@ -28,12 +28,14 @@ func NewContainerCommand(dockerCli *command.DockerCli) *cobra.Command {
 		NewPauseCommand(dockerCli),
 		NewPortCommand(dockerCli),
 		NewRenameCommand(dockerCli),
+		NewReplayCommand(dockerCli),
 		NewRestartCommand(dockerCli),
 		NewRmCommand(dockerCli),
 		NewRunCommand(dockerCli),
 		NewStartCommand(dockerCli),
 		NewStatsCommand(dockerCli),
 		NewStopCommand(dockerCli),
+		NewSyncCommand(dockerCli),
 		NewTopCommand(dockerCli),
 		NewUnpauseCommand(dockerCli),
 		NewUpdateCommand(dockerCli),
@@ -2,14 +2,19 @@ package container
 
 import (
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -21,6 +26,7 @@ type copyOptions struct {
 	destination string
 	followLink  bool
 	copyUIDGID  bool
+	excludes    opts.ListOpts
 }
 
 type copyDirection int
@@ -33,22 +39,49 @@ const (
 
 type cpConfig struct {
 	followLink bool
+	// excludes is nil if no exclude patterns are given
+	excludes *fileutils.PatternMatcher
+	// progress is nil if the progress of the copy is not shown
+	progress progress.Output
+}
+
+// filter leaves out the excluded entries of content
+func (c *cpConfig) filter(content io.ReadCloser, stripRoot bool) io.ReadCloser {
+	if c.excludes == nil {
+		return content
+	}
+	return rewriteArchive(content, excludeEntries(c.excludes, stripRoot))
+}
+
+// withProgress reports the bytes read from content, if the progress of the
+// copy is shown
+func (c *cpConfig) withProgress(content io.ReadCloser) io.ReadCloser {
+	if c.progress == nil {
+		return content
+	}
+	return progress.NewProgressReader(content, c.progress, 0, "", "Copying")
 }
 
 // NewCopyCommand creates a new `docker cp` command
 func NewCopyCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts copyOptions
+	opts := copyOptions{excludes: opts.NewListOpts(nil)}
 
 	cmd := &cobra.Command{
 		Use: `cp [OPTIONS] CONTAINER:SRC_PATH DEST_PATH|-
-	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH`,
+	docker cp [OPTIONS] SRC_PATH|- CONTAINER:DEST_PATH
+	docker cp [OPTIONS] CONTAINER:SRC_PATH CONTAINER:DEST_PATH`,
 		Short: "Copy files/folders between a container and the local filesystem",
 		Long: strings.Join([]string{
-			"Copy files/folders between a container and the local filesystem\n",
+			"Copy files/folders between a container and the local filesystem,\n",
+			"or between two containers\n",
 			"\nUse '-' as the source to read a tar archive from stdin\n",
 			"and extract it to a directory destination in a container.\n",
 			"Use '-' as the destination to stream a tar archive of a\n",
-			"container source to stdout.",
+			"container source to stdout.\n",
+			"\nThe last element of a container source path may be a glob\n",
+			"pattern, such as CONTAINER:/var/log/*.log, in which case the\n",
+			"destination must be an existing directory. A path which exists\n",
+			"in the container is copied as is, even if it contains *, ? or [.",
 		}, ""),
 		Args: cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -68,6 +101,7 @@ func NewCopyCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
+	flags.Var(&opts.excludes, "exclude", "Exclude files matching a pattern, in .dockerignore syntax")
 
 	return cmd
 }
@@ -87,6 +121,18 @@ func runCopy(dockerCli *command.DockerCli, opts copyOptions) error {
 	cpParam := &cpConfig{
 		followLink: opts.followLink,
 	}
+	if excludes := opts.excludes.GetAll(); len(excludes) > 0 {
+		excludeMatcher, err := fileutils.NewPatternMatcher(excludes)
+		if err != nil {
+			return errors.Wrap(err, "invalid exclude pattern")
+		}
+		cpParam.excludes = excludeMatcher
+	}
+	// The progress is only shown on a terminal, and never when the archive
+	// itself is written to stdout.
+	if dstPath != "-" && dockerCli.Out().IsTerminal() {
+		cpParam.progress = streamformatter.NewProgressOutput(dockerCli.Out())
+	}
 
 	ctx := context.Background()
 
@@ -96,15 +142,14 @@ func runCopy(dockerCli *command.DockerCli, opts copyOptions) error {
 	case toContainer:
 		return copyToContainer(ctx, dockerCli, srcPath, dstContainer, dstPath, cpParam, opts.copyUIDGID)
 	case acrossContainers:
-		// Copying between containers isn't supported.
-		return errors.New("copying between containers is not supported")
+		return copyAcrossContainers(ctx, dockerCli, srcContainer, srcPath, dstContainer, dstPath, cpParam, opts.copyUIDGID)
 	default:
 		// User didn't specify any container.
 		return errors.New("must specify at least one container source")
 	}
 }
 
-func statContainerPath(ctx context.Context, dockerCli *command.DockerCli, containerName, path string) (types.ContainerPathStat, error) {
+func statContainerPath(ctx context.Context, dockerCli command.Cli, containerName, path string) (types.ContainerPathStat, error) {
 	return dockerCli.Client().ContainerStatPath(ctx, containerName, path)
 }
 
@@ -125,6 +170,75 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 		}
 	}
 
+	content, srcInfo, glob, err := archiveFromContainer(ctx, dockerCli, srcContainer, srcPath, cpParam)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if dstPath == "-" {
+		// Send the response to STDOUT.
+		if _, err = io.Copy(os.Stdout, content); err != nil {
+			return err
+		}
+		return glob.checkMatched(srcContainer, srcPath)
+	}
+
+	if glob != nil {
+		if dstStat, err := os.Stat(dstPath); err != nil || !dstStat.IsDir() {
+			return errors.Errorf("destination %q must be an existing directory when copying multiple files", dstPath)
+		}
+	}
+
+	preArchive := cpParam.withProgress(content)
+
+	// See comments in the implementation of `archive.CopyTo` for exactly what
+	// goes into deciding how and whether the source archive needs to be
+	// altered for the correct copy behavior.
+	if err := archive.CopyTo(preArchive, srcInfo, dstPath); err != nil {
+		return err
+	}
+	if cpParam.progress != nil {
+		// Read the end of the archive, so the progress is completed.
+		if _, err := io.Copy(ioutil.Discard, preArchive); err != nil {
+			return err
+		}
+	}
+	return glob.checkMatched(srcContainer, srcPath)
+}
+
+// archiveFromContainer returns a tar archive of srcPath in srcContainer, with
+// its entries renamed if srcPath is a symbolic link which is followed and the
+// excluded entries left out, along with the copy info describing the source.
+// If the last element of srcPath is a glob pattern and srcPath does not exist,
+// the archive holds the matching entries of its parent directory, and the
+// returned globFilter records the matches.
+func archiveFromContainer(ctx context.Context, dockerCli command.Cli, srcContainer, srcPath string, cpParam *cpConfig) (io.ReadCloser, archive.CopyInfo, *globFilter, error) {
+	srcDir, pattern, err := splitGlob(srcPath)
+	if pattern != "" || err != nil {
+		// Names may contain glob characters, so a path which exists is
+		// copied as is.
+		if _, statErr := statContainerPath(ctx, dockerCli, srcContainer, srcPath); statErr == nil {
+			srcDir, pattern, err = srcPath, "", nil
+		}
+	}
+	if err != nil {
+		return nil, archive.CopyInfo{}, nil, err
+	}
+	if pattern != "" {
+		content, stat, err := dockerCli.Client().CopyFromContainer(ctx, srcContainer, srcDir)
+		if err != nil {
+			return nil, archive.CopyInfo{}, nil, err
+		}
+		if !stat.Mode.IsDir() {
+			content.Close()
+			return nil, archive.CopyInfo{}, nil, errors.Errorf("%s:%s is not a directory", srcContainer, srcDir)
+		}
+		glob := &globFilter{pattern: pattern}
+		srcInfo := archive.CopyInfo{Path: srcDir, Exists: true, IsDir: true}
+		return cpParam.filter(rewriteArchive(content, glob.rewrite), false), srcInfo, glob, nil
+	}
+
 	// if client requests to follow symbol link, then must decide target file to be copied
 	var rebaseName string
 	if cpParam.followLink {
@@ -147,15 +261,7 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 
 	content, stat, err := dockerCli.Client().CopyFromContainer(ctx, srcContainer, srcPath)
 	if err != nil {
-		return err
-	}
-	defer content.Close()
-
-	if dstPath == "-" {
-		// Send the response to STDOUT.
-		_, err = io.Copy(os.Stdout, content)
-
-		return err
+		return nil, archive.CopyInfo{}, nil, err
 	}
 
 	// Prepare source copy info.
@@ -166,15 +272,30 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 		RebaseName: rebaseName,
 	}
 
-	preArchive := content
-	if len(srcInfo.RebaseName) != 0 {
-		_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
-		preArchive = archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
+	if len(srcInfo.RebaseName) == 0 {
+		return cpParam.filter(content, true), srcInfo, nil, nil
 	}
-	// See comments in the implementation of `archive.CopyTo` for exactly what
-	// goes into deciding how and whether the source archive needs to be
-	// altered for the correct copy behavior.
-	return archive.CopyTo(preArchive, srcInfo, dstPath)
+	_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
+	rebased := archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
+	return cpParam.filter(readCloser{Reader: rebased, Closer: multiCloser{rebased, content}}, true), srcInfo, nil, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// multiCloser closes all of its closers, returning the first error
+type multiCloser []io.Closer
+
+func (closers multiCloser) Close() error {
+	var err error
+	for _, closer := range closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
 func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath, dstContainer, dstPath string, cpParam *cpConfig, copyUIDGID bool) (err error) {
@@ -186,46 +307,16 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 		}
 	}
 
-	// In order to get the copy behavior right, we need to know information
-	// about both the source and destination. The API is a simple tar
-	// archive/extract API but we can use the stat info header about the
-	// destination to be more informed about exactly what the destination is.
-
-	// Prepare destination copy info by stat-ing the container path.
-	dstInfo := archive.CopyInfo{Path: dstPath}
-	dstStat, err := statContainerPath(ctx, dockerCli, dstContainer, dstPath)
-
-	// If the destination is a symbolic link, we should evaluate it.
-	if err == nil && dstStat.Mode&os.ModeSymlink != 0 {
-		linkTarget := dstStat.LinkTarget
-		if !system.IsAbs(linkTarget) {
-			// Join with the parent directory.
-			dstParent, _ := archive.SplitPathDirEntry(dstPath)
-			linkTarget = filepath.Join(dstParent, linkTarget)
-		}
-
-		dstInfo.Path = linkTarget
-		dstStat, err = statContainerPath(ctx, dockerCli, dstContainer, linkTarget)
-	}
-
-	// Ignore any error and assume that the parent directory of the destination
-	// path exists, in which case the copy may still succeed. If there is any
-	// type of conflict (e.g., non-directory overwriting an existing directory
-	// or vice versa) the extraction will fail. If the destination simply did
-	// not exist, but the parent directory does, the extraction will still
-	// succeed.
-	if err == nil {
-		dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
-	}
+	dstInfo := containerDstInfo(ctx, dockerCli, dstContainer, dstPath)
 
 	var (
-		content         io.Reader
+		content         io.ReadCloser
 		resolvedDstPath string
 	)
 
 	if srcPath == "-" {
 		// Use STDIN.
-		content = os.Stdin
+		content = cpParam.filter(ioutil.NopCloser(os.Stdin), false)
 		resolvedDstPath = dstInfo.Path
 		if !dstInfo.IsDir {
 			return errors.Errorf("destination \"%s:%s\" must be a directory", dstContainer, dstPath)
@@ -241,6 +332,7 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 		if err != nil {
 			return err
 		}
+		srcArchive = cpParam.filter(srcArchive, true)
 		defer srcArchive.Close()
 
 		// With the stat info about the local source as well as the
@@ -270,7 +362,77 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 		CopyUIDGID:                copyUIDGID,
 	}
 
-	return dockerCli.Client().CopyToContainer(ctx, dstContainer, resolvedDstPath, content, options)
+	return dockerCli.Client().CopyToContainer(ctx, dstContainer, resolvedDstPath, cpParam.withProgress(content), options)
+}
+
+// copyAcrossContainers streams the archive of the source path in one
+// container straight into the other container, renaming its entries the same
+// way as when copying to or from the local filesystem.
+func copyAcrossContainers(ctx context.Context, dockerCli command.Cli, srcContainer, srcPath, dstContainer, dstPath string, cpParam *cpConfig, copyUIDGID bool) error {
+	dstInfo := containerDstInfo(ctx, dockerCli, dstContainer, dstPath)
+
+	srcArchive, srcInfo, glob, err := archiveFromContainer(ctx, dockerCli, srcContainer, srcPath, cpParam)
+	if err != nil {
+		return err
+	}
+	defer srcArchive.Close()
+
+	if glob != nil && !(dstInfo.Exists && dstInfo.IsDir) {
+		return errors.Errorf("destination \"%s:%s\" must be an existing directory when copying multiple files", dstContainer, dstPath)
+	}
+
+	dstDir, preparedArchive, err := archive.PrepareArchiveCopy(srcArchive, srcInfo, dstInfo)
+	if err != nil {
+		return err
+	}
+	defer preparedArchive.Close()
+
+	options := types.CopyToContainerOptions{
+		AllowOverwriteDirWithFile: false,
+		CopyUIDGID:                copyUIDGID,
+	}
+
+	if err := dockerCli.Client().CopyToContainer(ctx, dstContainer, dstDir, cpParam.withProgress(preparedArchive), options); err != nil {
+		return err
+	}
+	return glob.checkMatched(srcContainer, srcPath)
+}
+
+// containerDstInfo returns the copy info describing a destination path in a
+// container.
+func containerDstInfo(ctx context.Context, dockerCli command.Cli, dstContainer, dstPath string) archive.CopyInfo {
+	// In order to get the copy behavior right, we need to know information
+	// about both the source and destination. The API is a simple tar
+	// archive/extract API but we can use the stat info header about the
+	// destination to be more informed about exactly what the destination is.
+
+	// Prepare destination copy info by stat-ing the container path.
+	dstInfo := archive.CopyInfo{Path: dstPath}
+	dstStat, err := statContainerPath(ctx, dockerCli, dstContainer, dstPath)
+
+	// If the destination is a symbolic link, we should evaluate it.
+	if err == nil && dstStat.Mode&os.ModeSymlink != 0 {
+		linkTarget := dstStat.LinkTarget
+		if !system.IsAbs(linkTarget) {
+			// Join with the parent directory.
+			dstParent, _ := archive.SplitPathDirEntry(dstPath)
+			linkTarget = filepath.Join(dstParent, linkTarget)
+		}
+
+		dstInfo.Path = linkTarget
+		dstStat, err = statContainerPath(ctx, dockerCli, dstContainer, linkTarget)
+	}
+
+	// Ignore any error and assume that the parent directory of the destination
+	// path exists, in which case the copy may still succeed. If there is any
+	// type of conflict (e.g., non-directory overwriting an existing directory
+	// or vice versa) the extraction will fail. If the destination simply did
+	// not exist, but the parent directory does, the extraction will still
+	// succeed.
+	if err == nil {
+		dstInfo.Exists, dstInfo.IsDir = true, dstStat.Mode.IsDir()
+	}
+	return dstInfo
 }
 
 // We use `:` as a delimiter between CONTAINER and PATH, but `:` could also be
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/pkg/errors"
+)
+
+// rewriteFunc returns the new name of a tar entry, and whether the entry is
+// kept in the archive.
+type rewriteFunc func(name string) (string, bool, error)
+
+// rewriteArchive streams content, renaming its entries or leaving them out
+// as rewrite decides. content is closed once it has been read.
+func rewriteArchive(content io.ReadCloser, rewrite rewriteFunc) io.ReadCloser {
+	r, w := io.Pipe()
+
+	go func() {
+		defer content.Close()
+
+		srcTar := tar.NewReader(content)
+		rewrittenTar := tar.NewWriter(w)
+
+		for {
+			hdr, err := srcTar.Next()
+			if err == io.EOF {
+				w.CloseWithError(rewrittenTar.Close())
+				return
+			}
+			if err != nil {
+				w.CloseWithError(err)
+				return
+			}
+
+			name, keep, err := rewrite(hdr.Name)
+			if err != nil {
+				w.CloseWithError(err)
+				return
+			}
+			if !keep {
+				continue
+			}
+			hdr.Name = name
+			if hdr.Typeflag == tar.TypeLink {
+				if linkname, keep, err := rewrite(hdr.Linkname); err == nil && keep {
+					hdr.Linkname = linkname
+				}
+			}
+
+			if err := rewrittenTar.WriteHeader(hdr); err != nil {
+				w.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(rewrittenTar, srcTar); err != nil {
+				w.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// excludeEntries leaves out the entries matching the exclude patterns. The
+// patterns are matched against the entry names relative to the root entry of
+// the archive if stripRoot is set, or to the archive itself otherwise.
+func excludeEntries(excludes *fileutils.PatternMatcher, stripRoot bool) rewriteFunc {
+	return func(name string) (string, bool, error) {
+		rel := strings.TrimSuffix(name, "/")
+		if stripRoot {
+			i := strings.Index(rel, "/")
+			if i < 0 {
+				// The root entry itself is always copied.
+				return name, true, nil
+			}
+			rel = rel[i+1:]
+		}
+		excluded, err := excludes.Matches(filepath.FromSlash(rel))
+		if err != nil {
+			return "", false, err
+		}
+		return name, !excluded, nil
+	}
+}
+
+// globFilter keeps the entries of the archive of a directory whose first
+// path element below the directory matches a glob pattern, and renames them
+// to be relative to the directory.
+type globFilter struct {
+	pattern string
+	matches int
+}
+
+func (f *globFilter) rewrite(name string) (string, bool, error) {
+	i := strings.Index(strings.TrimSuffix(name, "/"), "/")
+	if i < 0 {
+		// Leave out the directory itself.
+		return "", false, nil
+	}
+	rel := name[i+1:]
+
+	matched, err := path.Match(f.pattern, strings.SplitN(rel, "/", 2)[0])
+	if err != nil || !matched {
+		return "", false, err
+	}
+	if !strings.Contains(strings.TrimSuffix(rel, "/"), "/") {
+		f.matches++
+	}
+	return rel, true, nil
+}
+
+// checkMatched returns an error if the pattern matched no entry. It is a
+// no-op on a nil globFilter.
+func (f *globFilter) checkMatched(container, srcPath string) error {
+	if f == nil || f.matches > 0 {
+		return nil
+	}
+	return errors.Errorf("no such file matching %s:%s", container, srcPath)
+}
+
+// splitGlob splits a container path whose last element is a glob pattern
+// into its parent directory and the pattern. pattern is empty if the last
+// element contains no glob pattern; glob characters in the other elements
+// are taken literally.
+func splitGlob(containerPath string) (dir, pattern string, err error) {
+	dir, pattern = path.Split(containerPath)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return containerPath, "", nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", "", errors.Errorf("invalid path %q: %v", containerPath, err)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	return path.Clean(dir), pattern, nil
+}
@@ -0,0 +1,113 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestArchive(t *testing.T, names ...string) io.ReadCloser {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg}
+		if name[len(name)-1] == '/' {
+			hdr.Mode, hdr.Typeflag = 0755, tar.TypeDir
+		} else {
+			hdr.Size = int64(len(name))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(name))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	return ioutil.NopCloser(buf)
+}
+
+func readTestArchive(t *testing.T, content io.Reader) []string {
+	var names []string
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestRewriteArchiveExcludes(t *testing.T) {
+	excludes, err := fileutils.NewPatternMatcher([]string{"*.tmp", "cache", "!cache/keep"})
+	require.NoError(t, err)
+
+	content := makeTestArchive(t,
+		"app/", "app/main.go", "app/main.tmp", "app/cache/", "app/cache/a", "app/cache/keep", "app/src/b.tmp")
+	names := readTestArchive(t, rewriteArchive(content, excludeEntries(excludes, true)))
+	assert.Equal(t, []string{"app/", "app/main.go", "app/cache/keep", "app/src/b.tmp"}, names)
+}
+
+func TestRewriteArchiveExcludesWithoutRoot(t *testing.T) {
+	excludes, err := fileutils.NewPatternMatcher([]string{"*.tmp"})
+	require.NoError(t, err)
+
+	content := makeTestArchive(t, "main.go", "main.tmp")
+	names := readTestArchive(t, rewriteArchive(content, excludeEntries(excludes, false)))
+	assert.Equal(t, []string{"main.go"}, names)
+}
+
+func TestRewriteArchiveGlob(t *testing.T) {
+	glob := &globFilter{pattern: "*.log"}
+
+	content := makeTestArchive(t,
+		"log/", "log/a.log", "log/b.txt", "log/old.log/", "log/old.log/c", "log/sub/", "log/sub/d.log")
+	names := readTestArchive(t, rewriteArchive(content, glob.rewrite))
+	assert.Equal(t, []string{"a.log", "old.log/", "old.log/c"}, names)
+	assert.Equal(t, 2, glob.matches)
+	assert.NoError(t, glob.checkMatched("ctr", "/var/log/*.log"))
+}
+
+func TestGlobCheckMatched(t *testing.T) {
+	var noGlob *globFilter
+	assert.NoError(t, noGlob.checkMatched("ctr", "/etc/hosts"))
+
+	glob := &globFilter{pattern: "*.log"}
+	readTestArchive(t, rewriteArchive(makeTestArchive(t, "log/", "log/a.txt"), glob.rewrite))
+	assert.EqualError(t, glob.checkMatched("ctr", "/var/log/*.log"), "no such file matching ctr:/var/log/*.log")
+}
+
+func TestSplitGlob(t *testing.T) {
+	testCases := []struct {
+		path            string
+		expectedDir     string
+		expectedPattern string
+		expectedErr     string
+	}{
+		{path: "/etc/hosts", expectedDir: "/etc/hosts"},
+		{path: "/var/log/*.log", expectedDir: "/var/log", expectedPattern: "*.log"},
+		{path: "/app-?", expectedDir: "/", expectedPattern: "app-?"},
+		{path: "data[0-9]", expectedDir: ".", expectedPattern: "data[0-9]"},
+		{path: "/data[1]/app.log", expectedDir: "/data[1]/app.log"},
+		{path: "/var/*/*.log", expectedDir: "/var/*", expectedPattern: "*.log"},
+		{path: "/var/log/[", expectedErr: "syntax error in pattern"},
+	}
+	for _, tc := range testCases {
+		dir, pattern, err := splitGlob(tc.path)
+		if tc.expectedErr != "" {
+			require.Error(t, err, tc.path)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+			continue
+		}
+		require.NoError(t, err, tc.path)
+		assert.Equal(t, tc.expectedDir, dir, tc.path)
+		assert.Equal(t, tc.expectedPattern, pattern, tc.path)
+	}
+}
@@ -0,0 +1,165 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// fakeContainerFiles serves the stat and archive calls of docker cp for the
+// files of containers, keyed by "container:path"
+type fakeContainerFiles map[string]types.ContainerPathStat
+
+func (files fakeContainerFiles) stat(container, path string) (types.ContainerPathStat, error) {
+	if _, ok := files[container+":"+path]; !ok {
+		return types.ContainerPathStat{}, errors.Errorf("Could not find the file %s in container %s", path, container)
+	}
+	return files[container+":"+path], nil
+}
+
+func TestCopyAcrossContainers(t *testing.T) {
+	files := fakeContainerFiles{
+		"src:/data/app.conf": {Name: "app.conf", Mode: 0644},
+		"dst:/etc/app":       {Name: "app", Mode: os.ModeDir | 0755},
+	}
+	var copied []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: files.stat,
+		copyFromContainerFunc: func(container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+			assert.Equal(t, "src", container)
+			stat, err := files.stat(container, path)
+			if err != nil {
+				return nil, stat, err
+			}
+			return makeTestArchive(t, "app.conf"), stat, nil
+		},
+		copyToContainerFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			assert.Equal(t, "dst", container)
+			assert.Equal(t, "/etc/app", path)
+			assert.True(t, options.CopyUIDGID)
+			copied = readTestArchive(t, content)
+			return nil
+		},
+	}, new(bytes.Buffer))
+
+	err := copyAcrossContainers(context.Background(), cli, "src", "/data/app.conf", "dst", "/etc/app", &cpConfig{}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app.conf"}, copied)
+}
+
+func TestCopyAcrossContainersRenames(t *testing.T) {
+	files := fakeContainerFiles{
+		"src:/data/app.conf": {Name: "app.conf", Mode: 0644},
+	}
+	var copied []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: files.stat,
+		copyFromContainerFunc: func(container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+			stat, err := files.stat(container, path)
+			return makeTestArchive(t, "app.conf"), stat, err
+		},
+		copyToContainerFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			assert.Equal(t, "/etc", path)
+			copied = readTestArchive(t, content)
+			return nil
+		},
+	}, new(bytes.Buffer))
+
+	err := copyAcrossContainers(context.Background(), cli, "src", "/data/app.conf", "dst", "/etc/renamed.conf", &cpConfig{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"renamed.conf"}, copied)
+}
+
+func TestCopyAcrossContainersMissingSource(t *testing.T) {
+	files := fakeContainerFiles{
+		"dst:/etc/app": {Name: "app", Mode: os.ModeDir | 0755},
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: files.stat,
+		copyFromContainerFunc: func(container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+			stat, err := files.stat(container, path)
+			return nil, stat, err
+		},
+		copyToContainerFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			t.Fatal("nothing should be copied to the destination")
+			return nil
+		},
+	}, new(bytes.Buffer))
+
+	err := copyAcrossContainers(context.Background(), cli, "src", "/data/missing.conf", "dst", "/etc/app", &cpConfig{}, false)
+	assert.EqualError(t, err, "Could not find the file /data/missing.conf in container src")
+}
+
+func TestCopyAcrossContainersMissingDestination(t *testing.T) {
+	files := fakeContainerFiles{
+		"src:/data/app.conf": {Name: "app.conf", Mode: 0644},
+	}
+	var copyCalls int
+	cli := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: files.stat,
+		copyFromContainerFunc: func(container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+			stat, err := files.stat(container, path)
+			return makeTestArchive(t, "app.conf"), stat, err
+		},
+		copyToContainerFunc: func(container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+			copyCalls++
+			io.Copy(ioutil.Discard, content)
+			return errors.Errorf("Error: No such container:path: %s:%s", container, path)
+		},
+	}, new(bytes.Buffer))
+
+	// a destination directory must exist
+	err := copyAcrossContainers(context.Background(), cli, "src", "/data/app.conf", "dst", "/missing/dir/", &cpConfig{}, false)
+	assert.Equal(t, archive.ErrDirNotExists, err)
+	assert.Equal(t, 0, copyCalls)
+
+	// the parent directory of a destination file is checked by the daemon
+	err = copyAcrossContainers(context.Background(), cli, "src", "/data/app.conf", "dst", "/missing/app.conf", &cpConfig{}, false)
+	assert.EqualError(t, err, "Error: No such container:path: dst:/missing")
+	assert.Equal(t, 1, copyCalls)
+}
+
+func TestArchiveFromContainerLiteralGlobCharacters(t *testing.T) {
+	files := fakeContainerFiles{
+		"ctr:/data/file[1].txt": {Name: "file[1].txt", Mode: 0644},
+		"ctr:/data":             {Name: "data", Mode: os.ModeDir | 0755},
+	}
+	var archived []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerStatPathFunc: files.stat,
+		copyFromContainerFunc: func(container, path string) (io.ReadCloser, types.ContainerPathStat, error) {
+			archived = append(archived, path)
+			stat, err := files.stat(container, path)
+			if path == "/data" {
+				return makeTestArchive(t, "data/", "data/file1.txt", "data/file[1].txt"), stat, err
+			}
+			return makeTestArchive(t, "file[1].txt"), stat, err
+		},
+	}, new(bytes.Buffer))
+	ctx := context.Background()
+
+	// an existing path is copied as is
+	content, srcInfo, glob, err := archiveFromContainer(ctx, cli, "ctr", "/data/file[1].txt", &cpConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, glob)
+	assert.Equal(t, "/data/file[1].txt", srcInfo.Path)
+	assert.Equal(t, []string{"file[1].txt"}, readTestArchive(t, content))
+
+	// a path which does not exist is matched as a glob pattern
+	content, srcInfo, glob, err = archiveFromContainer(ctx, cli, "ctr", "/data/file[0-9].txt", &cpConfig{})
+	require.NoError(t, err)
+	require.NotNil(t, glob)
+	assert.Equal(t, "/data", srcInfo.Path)
+	assert.Equal(t, []string{"file1.txt"}, readTestArchive(t, content))
+	assert.Equal(t, []string{"/data/file[1].txt", "/data"}, archived)
+}
@@ -20,8 +20,15 @@ import (
 	"golang.org/x/net/context"
 )
 
+const (
+	pullImageAlways  = "always"
+	pullImageMissing = "missing"
+	pullImageNever   = "never"
+)
+
 type createOptions struct {
 	name string
+	pull string
 }
 
 // NewCreateCommand creates a new cobra.Command for `docker create`
@@ -32,9 +39,11 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new container",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresImageOrProfile,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			copts.Image = args[0]
+			if len(args) > 0 {
+				copts.Image = args[0]
+			}
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
@@ -46,6 +55,7 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetInterspersed(false)
 
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
+	flags.StringVar(&opts.pull, "pull", pullImageMissing, `Pull image before creating ("always"|"missing"|"never")`)
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -62,7 +72,7 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *createOptions,
 		reportError(dockerCli.Err(), "create", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
 	}
-	response, err := createContainer(context.Background(), dockerCli, containerConfig, opts.name)
+	response, err := createContainer(context.Background(), dockerCli, containerConfig, opts.name, opts.pull)
 	if err != nil {
 		return err
 	}
@@ -146,7 +156,22 @@ func newCIDFile(path string) (*cidFile, error) {
 	return &cidFile{path: path, file: f}, nil
 }
 
-func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig *containerConfig, name string) (*container.ContainerCreateCreatedBody, error) {
+func validatePullPolicy(pull string) error {
+	switch pull {
+	case pullImageAlways, pullImageMissing, pullImageNever:
+		return nil
+	default:
+		return errors.Errorf("invalid pull policy %q: must be one of %s, %s or %s", pull, pullImageAlways, pullImageMissing, pullImageNever)
+	}
+}
+
+// createContainer creates a container, pulling its image first with the
+// "always" pull policy, or when the image is missing with the "missing" one.
+func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig *containerConfig, name, pull string) (*container.ContainerCreateCreatedBody, error) {
+	if err := validatePullPolicy(pull); err != nil {
+		return nil, err
+	}
+
 	config := containerConfig.Config
 	hostConfig := containerConfig.HostConfig
 	networkingConfig := containerConfig.NetworkingConfig
@@ -184,29 +209,42 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig
 		}
 	}
 
+	pullAndTagImage := func() error {
+		// we don't want to write to stdout anything apart from container.ID
+		if err := pullImage(ctx, dockerCli, config.Image, stderr); err != nil {
+			return err
+		}
+		if taggedRef, ok := namedRef.(reference.NamedTagged); ok && trustedRef != nil {
+			return image.TagTrusted(ctx, dockerCli, trustedRef, taggedRef)
+		}
+		return nil
+	}
+
+	if pull == pullImageAlways && namedRef != nil {
+		if err := pullAndTagImage(); err != nil {
+			return nil, err
+		}
+	}
+
 	//create the container
 	response, err := dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, name)
 
 	//if image not found try to pull it
 	if err != nil {
-		if apiclient.IsErrImageNotFound(err) && namedRef != nil {
+		if apiclient.IsErrImageNotFound(err) && namedRef != nil && pull == pullImageMissing {
 			fmt.Fprintf(stderr, "Unable to find image '%s' locally\n", reference.FamiliarString(namedRef))
 
-			// we don't want to write to stdout anything apart from container.ID
-			if err = pullImage(ctx, dockerCli, config.Image, stderr); err != nil {
+			if err := pullAndTagImage(); err != nil {
 				return nil, err
 			}
-			if taggedRef, ok := namedRef.(reference.NamedTagged); ok && trustedRef != nil {
-				if err := image.TagTrusted(ctx, dockerCli, trustedRef, taggedRef); err != nil {
-					return nil, err
-				}
-			}
 			// Retry
 			var retryErr error
 			response, retryErr = dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, name)
 			if retryErr != nil {
 				return nil, retryErr
 			}
+		} else if apiclient.IsErrImageNotFound(err) && namedRef != nil && pull == pullImageNever {
+			return nil, errors.Errorf("unable to find image '%s' locally, and --pull=%s prevents pulling it", reference.FamiliarString(namedRef), pullImageNever)
 		} else {
 			return nil, err
 		}
@@ -0,0 +1,97 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type fakeNotFound struct{}
+
+func (fakeNotFound) NotFound() bool { return true }
+func (fakeNotFound) Error() string  { return "No such image: example.com/app:1.0" }
+
+// fakeImageStore records the pulls of a fakeClient, and fails the creation
+// of containers until the image is pulled
+type fakeImageStore struct {
+	pulled  []string
+	present bool
+}
+
+func (s *fakeImageStore) client() *fakeClient {
+	return &fakeClient{
+		containerCreateFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ string) (container.ContainerCreateCreatedBody, error) {
+			if !s.present {
+				return container.ContainerCreateCreatedBody{}, fakeNotFound{}
+			}
+			return container.ContainerCreateCreatedBody{ID: "abc123"}, nil
+		},
+		imageCreateFunc: func(ref string, _ types.ImageCreateOptions) (io.ReadCloser, error) {
+			s.pulled = append(s.pulled, ref)
+			s.present = true
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		},
+	}
+}
+
+func createTestContainer(store *fakeImageStore, pull string) (*container.ContainerCreateCreatedBody, error) {
+	// Content trust is disabled by registering its flags without setting
+	// $DOCKER_CONTENT_TRUST.
+	command.AddTrustVerificationFlags(pflag.NewFlagSet("create", pflag.ContinueOnError))
+
+	cli := test.NewFakeCli(store.client(), new(bytes.Buffer))
+	cli.SetErr(new(bytes.Buffer))
+	config := &containerConfig{
+		Config:     &container.Config{Image: "example.com/app:1.0"},
+		HostConfig: &container.HostConfig{},
+	}
+	return createContainer(context.Background(), cli, config, "", pull)
+}
+
+func TestCreateContainerPullMissing(t *testing.T) {
+	store := &fakeImageStore{}
+	response, err := createTestContainer(store, pullImageMissing)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", response.ID)
+	assert.Equal(t, []string{"example.com/app:1.0"}, store.pulled)
+
+	store = &fakeImageStore{present: true}
+	_, err = createTestContainer(store, pullImageMissing)
+	require.NoError(t, err)
+	assert.Empty(t, store.pulled)
+}
+
+func TestCreateContainerPullAlways(t *testing.T) {
+	store := &fakeImageStore{present: true}
+	_, err := createTestContainer(store, pullImageAlways)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/app:1.0"}, store.pulled)
+}
+
+func TestCreateContainerPullNever(t *testing.T) {
+	store := &fakeImageStore{}
+	_, err := createTestContainer(store, pullImageNever)
+	assert.EqualError(t, err, "unable to find image 'example.com/app:1.0' locally, and --pull=never prevents pulling it")
+	assert.Empty(t, store.pulled)
+
+	store = &fakeImageStore{present: true}
+	_, err = createTestContainer(store, pullImageNever)
+	require.NoError(t, err)
+}
+
+func TestCreateContainerInvalidPull(t *testing.T) {
+	_, err := createTestContainer(&fakeImageStore{}, "sometimes")
+	assert.EqualError(t, err, `invalid pull policy "sometimes": must be one of always, missing or never`)
+}
@@ -1,8 +1,11 @@
 package container
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/cli/cli"
@@ -11,6 +14,8 @@ import (
 	"github.com/docker/docker/api/types"
 	apiclient "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/promise"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
@@ -23,12 +28,17 @@ type execOptions struct {
 	user        string
 	privileged  bool
 	env         *opts.ListOpts
+	filter      opts.FilterOpt
+	parallelism int
+	multiple    bool
+	record      string
 }
 
 func newExecOptions() *execOptions {
 	var values []string
 	return &execOptions{
-		env: opts.NewListOptsRef(&values, opts.ValidateEnv),
+		env:    opts.NewListOptsRef(&values, opts.ValidateEnv),
+		filter: opts.NewFilterOpt(),
 	}
 }
 
@@ -37,13 +47,30 @@ func NewExecCommand(dockerCli *command.DockerCli) *cobra.Command {
 	options := newExecOptions()
 
 	cmd := &cobra.Command{
-		Use:   "exec [OPTIONS] CONTAINER COMMAND [ARG...]",
-		Short: "Run a command in a running container",
-		Args:  cli.RequiresMinArgs(2),
+		Use: `exec [OPTIONS] CONTAINER COMMAND [ARG...]
+	docker exec [OPTIONS] --multiple CONTAINER [CONTAINER...] -- COMMAND [ARG...]
+	docker exec [OPTIONS] --filter FILTER [--] COMMAND [ARG...]`,
+		Short: "Run a command in one or more running containers",
+		Long: strings.Join([]string{
+			"Run a command in one or more running containers\n",
+			"\nWhen several containers are given with --multiple, or selected\n",
+			"with --filter, the command runs concurrently in all of them and\n",
+			"each line of its output is prefixed with the name of its container.\n",
+			"Both can be combined to run the command in the given containers\n",
+			"matching the filter.\n",
+			"The exit status is the highest exit status of the command.",
+		}, ""),
+		Args: cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			container := args[0]
-			execCmd := args[1:]
-			return runExec(dockerCli, options, container, execCmd)
+			filtered := options.filter.Value().Len() > 0
+			containers, execCmd, err := splitExecArgs(args, cmd.ArgsLenAtDash(), options.multiple, filtered)
+			if err != nil {
+				return err
+			}
+			if !options.multiple && !filtered {
+				return runExec(dockerCli, options, containers[0], execCmd)
+			}
+			return runExecMany(dockerCli, options, containers, execCmd)
 		},
 	}
 
@@ -58,10 +85,56 @@ func NewExecCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVarP(&options.privileged, "privileged", "", false, "Give extended privileges to the command")
 	flags.VarP(options.env, "env", "e", "Set environment variables")
 	flags.SetAnnotation("env", "version", []string{"1.25"})
+	flags.Var(&options.filter, "filter", "Run the command in the running containers matching a filter, as with 'docker ps --filter'")
+	flags.BoolVar(&options.multiple, "multiple", false, "Run the command in all the containers given before \"--\"")
+	flags.IntVar(&options.parallelism, "parallelism", 10, "Maximum number of containers running the command simultaneously (0 for no limit)")
+	flags.StringVar(&options.record, "record", "", "Record the session to a file in asciicast v2 format")
 
 	return cmd
 }
 
+// splitExecArgs splits the arguments into the containers and the command.
+// The first argument is the only container, unless several containers are
+// given with --multiple, separated from the command by "--", or the
+// containers are selected with --filter alone, in which case all the
+// arguments are the command. argsLenAtDash is the number of arguments before
+// the "--" parsed by the flags, or -1: as the flags are not interspersed,
+// only a "--" before the first argument is parsed, and the other ones are
+// left in the arguments.
+func splitExecArgs(args []string, argsLenAtDash int, multiple, filtered bool) ([]string, []string, error) {
+	switch {
+	case multiple:
+		var containers, execCmd []string
+		if argsLenAtDash >= 0 {
+			containers, execCmd = args[:argsLenAtDash], args[argsLenAtDash:]
+		} else {
+			// the containers come first, so the first "--" is the separator
+			i := 0
+			for i < len(args) && args[i] != "--" {
+				i++
+			}
+			if i == len(args) {
+				return nil, nil, errors.New(`"--" is required between the containers and the command with --multiple`)
+			}
+			containers, execCmd = args[:i], args[i+1:]
+		}
+		if len(containers) == 0 && !filtered {
+			return nil, nil, errors.New("at least one container, or --filter, is required")
+		}
+		if len(execCmd) == 0 {
+			return nil, nil, errors.New("a command is required")
+		}
+		return containers, execCmd, nil
+	case filtered:
+		return nil, args, nil
+	default:
+		if len(args) < 2 {
+			return nil, nil, errors.New("a command is required")
+		}
+		return args[:1], args[1:], nil
+	}
+}
+
 // nolint: gocyclo
 func runExec(dockerCli *command.DockerCli, options *execOptions, container string, execCmd []string) error {
 	execConfig, err := parseExec(options, execCmd)
@@ -91,6 +164,8 @@ func runExec(dockerCli *command.DockerCli, options *execOptions, container strin
 		if err := dockerCli.In().CheckTty(execConfig.AttachStdin, execConfig.Tty); err != nil {
 			return err
 		}
+	} else if options.record != "" {
+		return errors.New("Conflicting options: --record and -d")
 	}
 
 	response, err := client.ContainerExecCreate(ctx, container, *execConfig)
@@ -140,6 +215,16 @@ func runExec(dockerCli *command.DockerCli, options *execOptions, container strin
 		}
 	}
 
+	var recorder *sessionRecorder
+	if options.record != "" {
+		title := "docker exec " + container + " " + strings.Join(execCmd, " ")
+		if recorder, err = newSessionRecorder(dockerCli, options.record, title); err != nil {
+			return err
+		}
+		defer recorder.Close()
+		in, out, stderr = recorder.input(in), recorder.output(out), recorder.output(stderr)
+	}
+
 	resp, err := client.ContainerExecAttach(ctx, execID, *execConfig)
 	if err != nil {
 		return err
@@ -150,7 +235,7 @@ func runExec(dockerCli *command.DockerCli, options *execOptions, container strin
 	})
 
 	if execConfig.Tty && dockerCli.In().IsTerminal() {
-		if err := MonitorTtySize(ctx, dockerCli, execID, true); err != nil {
+		if err := monitorTtySize(ctx, dockerCli, execID, true, recorder); err != nil {
 			fmt.Fprintln(dockerCli.Err(), "Error monitoring TTY size:", err)
 		}
 	}
@@ -159,6 +244,9 @@ func runExec(dockerCli *command.DockerCli, options *execOptions, container strin
 		logrus.Debugf("Error hijack: %s", err)
 		return err
 	}
+	if err := recorder.Close(); err != nil {
+		fmt.Fprintln(dockerCli.Err(), "Error writing the recording:", err)
+	}
 
 	var status int
 	if _, status, err = getExecExitCode(ctx, client, execID); err != nil {
@@ -172,6 +260,168 @@ func runExec(dockerCli *command.DockerCli, options *execOptions, container strin
 	return nil
 }
 
+// runExecMany runs the command concurrently in the containers, and in those
+// matching the filter.
+func runExecMany(dockerCli *command.DockerCli, options *execOptions, refs []string, execCmd []string) error {
+	if options.tty || options.interactive {
+		return errors.New("--tty and --interactive can not be used when running a command in several containers")
+	}
+	if options.record != "" {
+		return errors.New("--record can not be used when running a command in several containers")
+	}
+	execConfig, err := parseExec(options, execCmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client := dockerCli.Client()
+
+	var (
+		ids, names []string
+		seen       = map[string]bool{}
+	)
+	for _, ref := range refs {
+		c, err := client.ContainerInspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			ids = append(ids, c.ID)
+			names = append(names, strings.TrimPrefix(c.Name, "/"))
+		}
+	}
+	if options.filter.Value().Len() > 0 {
+		list, err := client.ContainerList(ctx, types.ContainerListOptions{Filters: options.filter.Value()})
+		if err != nil {
+			return err
+		}
+		for _, c := range list {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				ids = append(ids, c.ID)
+				names = append(names, strings.TrimPrefix(c.Names[0], "/"))
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return errors.New("no running containers match the filter")
+	}
+
+	prefixes := logPrefixes(names, true, dockerCli.Out().IsTerminal())
+	statuses := make([]int, len(ids))
+	errs := make([]error, len(ids))
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem chan struct{}
+	)
+	if options.parallelism > 0 {
+		sem = make(chan struct{}, options.parallelism)
+	}
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			stdout := &linePrefixWriter{mu: &mu, w: dockerCli.Out(), prefix: prefixes[i]}
+			stderr := &linePrefixWriter{mu: &mu, w: dockerCli.Err(), prefix: prefixes[i]}
+			statuses[i], errs[i] = execInContainer(ctx, dockerCli, ids[i], *execConfig, stdout, stderr)
+			stdout.flush()
+			stderr.flush()
+		}(i)
+	}
+	wg.Wait()
+
+	status := 0
+	for i := range ids {
+		switch {
+		case errs[i] != nil:
+			fmt.Fprintf(dockerCli.Err(), "%s: %v\n", names[i], errs[i])
+			statuses[i] = 126
+		case statuses[i] != 0:
+			fmt.Fprintf(dockerCli.Err(), "%s: exit status %d\n", names[i], statuses[i])
+		}
+		if statuses[i] > status {
+			status = statuses[i]
+		}
+	}
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// execInContainer runs a command in a container without a TTY nor input,
+// and returns its exit status once it completes. If execConfig is detached,
+// it returns once the command is started.
+func execInContainer(ctx context.Context, dockerCli *command.DockerCli, container string, execConfig types.ExecConfig, stdout, stderr io.Writer) (int, error) {
+	client := dockerCli.Client()
+
+	response, err := client.ContainerExecCreate(ctx, container, execConfig)
+	if err != nil {
+		return -1, err
+	}
+	if execConfig.Detach {
+		return 0, client.ContainerExecStart(ctx, response.ID, types.ExecStartCheck{Detach: true})
+	}
+
+	resp, err := client.ContainerExecAttach(ctx, response.ID, execConfig)
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Close()
+
+	if _, err := stdcopy.StdCopy(stdout, stderr, resp.Reader); err != nil {
+		return -1, err
+	}
+
+	_, status, err := getExecExitCode(ctx, client, response.ID)
+	return status, err
+}
+
+// linePrefixWriter writes each line written to it with a prefix. Writers
+// sharing a mutex write whole lines, so their lines do not mix.
+type linePrefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf.Next(i + 1)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush writes the last line if it does not end with a newline
+func (w *linePrefixWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.writeLine(append(w.buf.Bytes(), '\n'))
+		w.buf.Reset()
+	}
+}
+
+func (w *linePrefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.w.Write(append([]byte(w.prefix), line...))
+	return err
+}
+
 // getExecExitCode perform an inspect on the exec command. It returns
 // the running state and the exit code.
 func getExecExitCode(ctx context.Context, client apiclient.ContainerAPIClient, execID string) (bool, int, error) {
@@ -1,9 +1,14 @@
 package container
 
 import (
+	"bytes"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type arguments struct {
@@ -114,3 +119,106 @@ func compareExecConfig(config1 *types.ExecConfig, config2 *types.ExecConfig) boo
 	}
 	return true
 }
+
+func TestSplitExecArgs(t *testing.T) {
+	testCases := []struct {
+		args               []string
+		expectedContainers []string
+		expectedCmd        []string
+		expectedErr        string
+	}{
+		{
+			args:               []string{"web", "ls", "-l"},
+			expectedContainers: []string{"web"},
+			expectedCmd:        []string{"ls", "-l"},
+		},
+		{
+			args:               []string{"--multiple", "web", "db", "--", "ls"},
+			expectedContainers: []string{"web", "db"},
+			expectedCmd:        []string{"ls"},
+		},
+		{
+			// without --multiple, "--" is part of the command
+			args:               []string{"web", "grep", "--", "-v", "file"},
+			expectedContainers: []string{"web"},
+			expectedCmd:        []string{"grep", "--", "-v", "file"},
+		},
+		{
+			// only the first "--" separates the containers
+			args:               []string{"--multiple", "web", "--", "grep", "--", "-v", "file"},
+			expectedContainers: []string{"web"},
+			expectedCmd:        []string{"grep", "--", "-v", "file"},
+		},
+		{
+			args:        []string{"--multiple", "web", "db", "ls"},
+			expectedErr: `"--" is required between the containers and the command with --multiple`,
+		},
+		{
+			args:        []string{"--filter", "label=app=web", "ls", "-l"},
+			expectedCmd: []string{"ls", "-l"},
+		},
+		{
+			// with --filter alone, every argument is the command
+			args:        []string{"--filter", "label=app=web", "--", "grep", "--", "-v", "foo", "/etc/hosts"},
+			expectedCmd: []string{"grep", "--", "-v", "foo", "/etc/hosts"},
+		},
+		{
+			args:        []string{"--filter", "label=app=web", "grep", "--", "-v", "foo"},
+			expectedCmd: []string{"grep", "--", "-v", "foo"},
+		},
+		{
+			args:               []string{"--filter", "label=app=web", "--multiple", "web", "--", "ls"},
+			expectedContainers: []string{"web"},
+			expectedCmd:        []string{"ls"},
+		},
+		{
+			args:               []string{"--filter", "label=app=web", "--multiple", "--", "ls"},
+			expectedContainers: []string{},
+			expectedCmd:        []string{"ls"},
+		},
+		{
+			args:        []string{"--multiple", "--", "ls"},
+			expectedErr: "at least one container, or --filter, is required",
+		},
+		{
+			args:        []string{"--multiple", "web", "--"},
+			expectedErr: "a command is required",
+		},
+		{
+			args:        []string{"web"},
+			expectedErr: "a command is required",
+		},
+	}
+	for _, tc := range testCases {
+		msg := strings.Join(tc.args, " ")
+		cmd := NewExecCommand(nil)
+		require.NoError(t, cmd.ParseFlags(tc.args), msg)
+		multiple, err := cmd.Flags().GetBool("multiple")
+		require.NoError(t, err)
+		filtered := cmd.Flags().Changed("filter")
+
+		containers, execCmd, err := splitExecArgs(cmd.Flags().Args(), cmd.ArgsLenAtDash(), multiple, filtered)
+		if tc.expectedErr != "" {
+			assert.EqualError(t, err, tc.expectedErr, msg)
+			continue
+		}
+		require.NoError(t, err, msg)
+		assert.Equal(t, tc.expectedContainers, containers, msg)
+		assert.Equal(t, tc.expectedCmd, execCmd, msg)
+	}
+}
+
+func TestLinePrefixWriter(t *testing.T) {
+	var mu sync.Mutex
+	out := new(bytes.Buffer)
+	web := &linePrefixWriter{mu: &mu, w: out, prefix: "web | "}
+	db := &linePrefixWriter{mu: &mu, w: out, prefix: "db  | "}
+
+	web.Write([]byte("one\ntw"))
+	db.Write([]byte("three\n"))
+	web.Write([]byte("o\nfour"))
+	web.flush()
+	db.flush()
+
+	assert.Equal(t, "web | one\ndb  | three\nweb | two\nweb | four\n", out.String())
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -13,18 +13,20 @@ import (
 
 type killOptions struct {
 	signal string
+	filter opts.FilterOpt
+	force  bool
 
 	containers []string
 }
 
 // NewKillCommand creates a new cobra.Command for `docker kill`
 func NewKillCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts killOptions
+	opts := killOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "kill [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Kill one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runKill(dockerCli, &opts)
@@ -33,16 +35,22 @@ func NewKillCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.signal, "signal", "s", "KILL", "Signal to send to the container")
+	flags.Var(&opts.filter, "filter", "Filter the containers to kill, as with 'docker ps --filter'")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
 	return cmd
 }
 
 func runKill(dockerCli *command.DockerCli, opts *killOptions) error {
 	var errs []string
 	ctx := context.Background()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "killed", false, opts.force)
+	if err != nil {
+		return err
+	}
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
 		return dockerCli.Client().ContainerKill(ctx, container, opts.signal)
 	})
-	for _, name := range opts.containers {
+	for _, name := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 		} else {
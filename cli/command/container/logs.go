@@ -1,12 +1,19 @@
 package container
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"strings"
+	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/jsonlog"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
@@ -14,23 +21,32 @@ import (
 type logsOptions struct {
 	follow     bool
 	since      string
+	until      string
 	timestamps bool
 	details    bool
 	tail       string
+	filter     opts.FilterOpt
+	reattach   bool
 
-	container string
+	containers []string
 }
 
 // NewLogsCommand creates a new cobra.Command for `docker logs`
 func NewLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts logsOptions
+	opts := logsOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
-		Use:   "logs [OPTIONS] CONTAINER",
-		Short: "Fetch the logs of a container",
-		Args:  cli.ExactArgs(1),
+		Use:   "logs [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Fetch the logs of one or more containers",
+		Long: strings.Join([]string{
+			"Fetch the logs of one or more containers\n",
+			"\nThe logs of several containers, given as arguments or selected\n",
+			"with --filter, are merged by timestamp and each line is prefixed\n",
+			"with the name of its container.",
+		}, ""),
+		Args: requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.container = args[0]
+			opts.containers = args
 			return runLogs(dockerCli, &opts)
 		},
 	}
@@ -38,15 +54,47 @@ func NewLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.StringVar(&opts.until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
 	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
 	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
 	flags.StringVar(&opts.tail, "tail", "all", "Number of lines to show from the end of the logs")
+	flags.Var(&opts.filter, "filter", "Select the containers whose logs are shown, as with 'docker ps --filter'")
+	flags.BoolVar(&opts.reattach, "reattach", false, "Keep following the logs when the container restarts or is replaced by a container with the same name, until interrupted")
 	return cmd
 }
 
 func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 	ctx := context.Background()
 
+	if opts.reattach {
+		if !opts.follow {
+			return errors.New("--reattach requires --follow")
+		}
+		if len(opts.containers) != 1 || opts.filter.Value().Len() > 0 || opts.until != "" {
+			return errors.New("--reattach can only be used with a single container, without --filter or --until")
+		}
+		return followContainerLogs(ctx, dockerCli, opts)
+	}
+
+	if len(opts.containers) == 1 && opts.filter.Value().Len() == 0 && opts.until == "" {
+		return writeContainerLogs(ctx, dockerCli, opts)
+	}
+
+	containers, err := selectLogsContainers(ctx, dockerCli, opts)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return errors.New("no containers match the filter")
+	}
+	return writeMergedLogs(ctx, dockerCli, opts, containers)
+}
+
+// writeContainerLogs copies the logs of a single container as they are sent
+// by the daemon.
+func writeContainerLogs(ctx context.Context, dockerCli *command.DockerCli, opts *logsOptions) error {
+	container := opts.containers[0]
+
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -56,13 +104,13 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 		Tail:       opts.tail,
 		Details:    opts.details,
 	}
-	responseBody, err := dockerCli.Client().ContainerLogs(ctx, opts.container, options)
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, container, options)
 	if err != nil {
 		return err
 	}
 	defer responseBody.Close()
 
-	c, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
+	c, err := dockerCli.Client().ContainerInspect(ctx, container)
 	if err != nil {
 		return err
 	}
@@ -74,3 +122,464 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 	}
 	return err
 }
+
+// followContainerLogs follows the logs of a container across its restarts,
+// and across the containers which replace it under the same name. It only
+// returns on an error, or once ctx is done.
+func followContainerLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
+	c, err := dockerCli.Client().ContainerInspect(ctx, opts.containers[0])
+	if err != nil {
+		return err
+	}
+	containerID := c.ID
+	name := strings.TrimPrefix(c.Name, "/")
+	tty := c.Config.Tty
+
+	// Subscribe to the events before streaming the logs, so that no restart
+	// is missed.
+	f := filters.NewArgs()
+	f.Add("type", "container")
+	f.Add("container", containerID)
+	f.Add("container", name)
+	eventCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	eventq, errq := dockerCli.Client().Events(eventCtx, types.EventsOptions{Filters: f})
+
+	// The logs are always streamed with timestamps, so that a restarted
+	// stream resumes after the last line already written.
+	stdout := &resumeLogWriter{w: dockerCli.Out(), timestamps: opts.timestamps}
+	stderr := &resumeLogWriter{w: dockerCli.Err(), timestamps: opts.timestamps}
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.since,
+		Timestamps: true,
+		Follow:     true,
+		Tail:       opts.tail,
+		Details:    opts.details,
+	}
+
+	for {
+		if err := streamResumedLogs(ctx, dockerCli, containerID, tty, options, stdout, stderr); err != nil {
+			return err
+		}
+
+		last := stdout.last
+		if stderr.last.After(last) {
+			last = stderr.last
+		}
+		stdout.after, stderr.after = last, last
+		if !last.IsZero() {
+			options.Since = fmt.Sprintf("%d.%09d", last.Unix(), last.Nanosecond())
+			options.Tail = "all"
+		}
+
+		// Wait for the container, or one replacing it, to start. A removed
+		// container may be replaced later, so its destroy event is not the
+		// end of the logs.
+		for started := false; !started; {
+			select {
+			case event := <-eventq:
+				if event.Status != "start" {
+					continue
+				}
+				if event.Actor.ID != containerID {
+					if event.Actor.Attributes["name"] != name {
+						continue
+					}
+					c, err := dockerCli.Client().ContainerInspect(ctx, event.Actor.ID)
+					if err != nil {
+						return err
+					}
+					containerID, tty = c.ID, c.Config.Tty
+				}
+				started = true
+			case err := <-errq:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func streamResumedLogs(ctx context.Context, dockerCli command.Cli, containerID string, tty bool, options types.ContainerLogsOptions, stdout, stderr *resumeLogWriter) error {
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	if tty {
+		_, err = io.Copy(stdout, responseBody)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+	}
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// resumeLogWriter writes timestamped log lines, leaving out those which are
+// not after a given time, as a resumed stream repeats the last lines of the
+// previous one.
+type resumeLogWriter struct {
+	w io.Writer
+	// timestamps is set if the timestamps are written with the lines
+	timestamps bool
+	// after is the timestamp of the last line written by a previous stream
+	after time.Time
+	// last is the timestamp of the last line written
+	last time.Time
+	buf  bytes.Buffer
+}
+
+func (w *resumeLogWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf.Next(i + 1)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush writes the last line if it does not end with a newline
+func (w *resumeLogWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.writeLine(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+func (w *resumeLogWriter) writeLine(line []byte) error {
+	if parts := bytes.SplitN(line, []byte(" "), 2); len(parts) == 2 {
+		if timestamp, err := time.Parse(jsonlog.RFC3339NanoFixed, string(parts[0])); err == nil {
+			if !timestamp.After(w.after) {
+				return nil
+			}
+			w.last = timestamp
+			if !w.timestamps {
+				line = parts[1]
+			}
+		}
+	}
+	_, err := w.w.Write(line)
+	return err
+}
+
+// logsContainer is a container whose logs are merged with others
+type logsContainer struct {
+	id   string
+	name string
+	tty  bool
+}
+
+// selectLogsContainers returns the containers given as arguments, followed by
+// those matching the filter.
+func selectLogsContainers(ctx context.Context, dockerCli *command.DockerCli, opts *logsOptions) ([]logsContainer, error) {
+	var (
+		containers []logsContainer
+		seen       = map[string]bool{}
+	)
+	add := func(ref string) error {
+		c, err := dockerCli.Client().ContainerInspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			containers = append(containers, logsContainer{
+				id:   c.ID,
+				name: strings.TrimPrefix(c.Name, "/"),
+				tty:  c.Config != nil && c.Config.Tty,
+			})
+		}
+		return nil
+	}
+
+	for _, ref := range opts.containers {
+		if err := add(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.filter.Value().Len() > 0 {
+		list, err := dockerCli.Client().ContainerList(ctx, types.ContainerListOptions{
+			All:     true,
+			Filters: opts.filter.Value(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range list {
+			if err := add(c.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return containers, nil
+}
+
+// writeMergedLogs writes the logs of the containers interleaved by their
+// timestamp, each line prefixed by the name of its container.
+func writeMergedLogs(ctx context.Context, dockerCli *command.DockerCli, opts *logsOptions, containers []logsContainer) error {
+	until, err := command.ParseUntil(opts.until)
+	if err != nil {
+		return err
+	}
+
+	// The daemon does not filter logs by end time, and the lines of the
+	// containers are ordered by their timestamp, so timestamps are always
+	// requested.
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.since,
+		Timestamps: true,
+		Follow:     opts.follow,
+		Tail:       opts.tail,
+		Details:    opts.details,
+	}
+	if !until.IsZero() && opts.follow {
+		if until.After(time.Now()) {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, until)
+			defer cancel()
+		} else {
+			options.Follow = false
+		}
+	}
+
+	names := make([]string, len(containers))
+	for i, c := range containers {
+		names[i] = c.name
+	}
+	prefixes := logPrefixes(names, len(containers) > 1 || opts.filter.Value().Len() > 0, dockerCli.Out().IsTerminal())
+
+	lines := make(chan logLine)
+	for i, c := range containers {
+		go func(i int, c logsContainer) {
+			err := streamContainerLogs(ctx, dockerCli, c, options, i, lines)
+			if err != nil && ctx.Err() == context.DeadlineExceeded {
+				// the stream was closed because --until was reached
+				err = nil
+			}
+			lines <- logLine{source: i, end: true, err: err}
+		}(i, c)
+	}
+
+	var errs []string
+	merger := newLogMerger(len(containers))
+	ticker := time.NewTicker(logMergeWindow / 2)
+	defer ticker.Stop()
+
+	for !merger.done() {
+		select {
+		case line := <-lines:
+			if line.end {
+				merger.end(line.source)
+				if line.err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %v", containers[line.source].name, line.err))
+				}
+			} else {
+				merger.add(line)
+			}
+		case <-ticker.C:
+		}
+
+		for {
+			line, ok := merger.next(time.Now())
+			if !ok {
+				break
+			}
+			if !until.IsZero() && line.timestamp.After(until) {
+				continue
+			}
+			out := io.Writer(dockerCli.Out())
+			if line.stderr {
+				out = dockerCli.Err()
+			}
+			fmt.Fprint(out, formatLogLine(prefixes[line.source], line, opts.timestamps))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// logColors are the ANSI colors of the container names on a terminal
+var logColors = []int{36, 33, 32, 35, 34, 31}
+
+// logPrefixes returns the prefix of the lines of each container, which is
+// its name padded to the longest name, and colored on a terminal.
+func logPrefixes(names []string, prefix, color bool) []string {
+	prefixes := make([]string, len(names))
+	if !prefix {
+		return prefixes
+	}
+
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	for i, name := range names {
+		name = fmt.Sprintf("%-*s", width, name)
+		if color {
+			name = fmt.Sprintf("\033[%dm%s\033[0m", logColors[i%len(logColors)], name)
+		}
+		prefixes[i] = name + " | "
+	}
+	return prefixes
+}
+
+func formatLogLine(prefix string, line logLine, timestamps bool) string {
+	if timestamps {
+		return prefix + line.timestamp.UTC().Format(jsonlog.RFC3339NanoFixed) + " " + line.message
+	}
+	return prefix + line.message
+}
+
+// logLine is a line of the logs of a container, or the end of its logs
+type logLine struct {
+	source    int
+	stderr    bool
+	timestamp time.Time
+	message   string
+	received  time.Time
+
+	end bool
+	err error
+}
+
+// streamContainerLogs sends the lines of the logs of container to lines
+func streamContainerLogs(ctx context.Context, dockerCli *command.DockerCli, container logsContainer, options types.ContainerLogsOptions, source int, lines chan<- logLine) error {
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, container.id, options)
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	stdout := &logLineWriter{source: source, lines: lines}
+	if container.tty {
+		_, err = io.Copy(stdout, responseBody)
+		stdout.flush()
+		return err
+	}
+	stderr := &logLineWriter{source: source, stderr: true, lines: lines}
+	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// logLineWriter splits the timestamped logs written to it into lines
+type logLineWriter struct {
+	source int
+	stderr bool
+	lines  chan<- logLine
+	buf    bytes.Buffer
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.send(string(w.buf.Next(i + 1)))
+	}
+}
+
+// flush sends the last line if it does not end with a newline
+func (w *logLineWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.send(w.buf.String() + "\n")
+		w.buf.Reset()
+	}
+}
+
+func (w *logLineWriter) send(text string) {
+	line := logLine{source: w.source, stderr: w.stderr, message: text, received: time.Now()}
+	if parts := strings.SplitN(text, " ", 2); len(parts) == 2 {
+		if timestamp, err := time.Parse(jsonlog.RFC3339NanoFixed, parts[0]); err == nil {
+			line.timestamp = timestamp
+			line.message = parts[1]
+		}
+	}
+	w.lines <- line
+}
+
+// logMergeWindow is how long a line is held back, waiting for older lines
+// of other containers
+const logMergeWindow = 200 * time.Millisecond
+
+// logMerger orders the lines of several containers by their timestamp. A
+// line is released once every container which is still streaming has a
+// line pending, which makes the order exact for past logs, or once it has
+// been held back for logMergeWindow, so that following the logs of a quiet
+// container does not hold back the others.
+type logMerger struct {
+	pending [][]logLine
+	ended   []bool
+}
+
+func newLogMerger(sources int) *logMerger {
+	return &logMerger{
+		pending: make([][]logLine, sources),
+		ended:   make([]bool, sources),
+	}
+}
+
+func (m *logMerger) add(line logLine) {
+	m.pending[line.source] = append(m.pending[line.source], line)
+}
+
+func (m *logMerger) end(source int) {
+	m.ended[source] = true
+}
+
+// done returns whether all the sources ended and all their lines were
+// released
+func (m *logMerger) done() bool {
+	for i := range m.pending {
+		if !m.ended[i] || len(m.pending[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// next returns the oldest pending line, if it can be released at now
+func (m *logMerger) next(now time.Time) (logLine, bool) {
+	oldest := -1
+	complete := true
+	for i, lines := range m.pending {
+		if len(lines) == 0 {
+			if !m.ended[i] {
+				complete = false
+			}
+			continue
+		}
+		if oldest < 0 || lines[0].timestamp.Before(m.pending[oldest][0].timestamp) {
+			oldest = i
+		}
+	}
+	if oldest < 0 {
+		return logLine{}, false
+	}
+	line := m.pending[oldest][0]
+	if !complete && now.Sub(line.received) < logMergeWindow {
+		return logLine{}, false
+	}
+	m.pending[oldest] = m.pending[oldest][1:]
+	return line, true
+}
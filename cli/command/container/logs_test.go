@@ -0,0 +1,167 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestLogLineWriter(t *testing.T) {
+	lines := make(chan logLine, 10)
+	w := &logLineWriter{source: 1, stderr: true, lines: lines}
+
+	_, err := w.Write([]byte("2017-06-01T10:00:00.000000001Z first\n2017-06-01T10:00:01.000000000Z sec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\nno timestamp"))
+	require.NoError(t, err)
+	w.flush()
+	close(lines)
+
+	var received []logLine
+	for line := range lines {
+		received = append(received, line)
+	}
+	require.Len(t, received, 3)
+	assert.Equal(t, "first\n", received[0].message)
+	assert.Equal(t, time.Date(2017, 6, 1, 10, 0, 0, 1, time.UTC), received[0].timestamp.UTC())
+	assert.Equal(t, "second\n", received[1].message)
+	assert.Equal(t, "no timestamp\n", received[2].message)
+	assert.True(t, received[2].timestamp.IsZero())
+	assert.Equal(t, 1, received[2].source)
+	assert.True(t, received[2].stderr)
+}
+
+func TestLogMergerOrdersByTimestamp(t *testing.T) {
+	base := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	now := time.Now()
+	line := func(source, second int) logLine {
+		return logLine{source: source, timestamp: base.Add(time.Duration(second) * time.Second), received: now}
+	}
+
+	m := newLogMerger(2)
+	m.add(line(0, 1))
+	m.add(line(0, 3))
+
+	// the second container may still send older lines
+	_, ok := m.next(now)
+	assert.False(t, ok)
+
+	m.add(line(1, 2))
+	m.end(1)
+
+	var seconds []int
+	for {
+		next, ok := m.next(now)
+		if !ok {
+			break
+		}
+		seconds = append(seconds, int(next.timestamp.Sub(base)/time.Second))
+	}
+	assert.Equal(t, []int{1, 2, 3}, seconds)
+	assert.False(t, m.done())
+
+	m.end(0)
+	assert.True(t, m.done())
+}
+
+func TestLogMergerReleasesAfterWindow(t *testing.T) {
+	now := time.Now()
+	m := newLogMerger(2)
+	m.add(logLine{source: 0, timestamp: now, received: now})
+
+	_, ok := m.next(now)
+	assert.False(t, ok)
+
+	next, ok := m.next(now.Add(logMergeWindow))
+	assert.True(t, ok)
+	assert.Equal(t, 0, next.source)
+}
+
+func TestLogPrefixes(t *testing.T) {
+	containers := []string{"web", "database"}
+
+	assert.Equal(t, []string{"", ""}, logPrefixes(containers, false, true))
+	assert.Equal(t, []string{"web      | ", "database | "}, logPrefixes(containers, true, false))
+	assert.Equal(t, []string{"\033[36mweb     \033[0m | ", "\033[33mdatabase\033[0m | "}, logPrefixes(containers, true, true))
+}
+
+func TestFormatLogLine(t *testing.T) {
+	line := logLine{timestamp: time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC), message: "hello\n"}
+
+	assert.Equal(t, "web | hello\n", formatLogLine("web | ", line, false))
+	assert.Equal(t, "web | 2017-06-01T10:00:00.000000000Z hello\n", formatLogLine("web | ", line, true))
+}
+
+func TestResumeLogWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := &resumeLogWriter{w: out}
+
+	_, err := w.Write([]byte("2017-06-01T10:00:00.000000000Z first\n2017-06-01T10:00:01.000000000Z second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", out.String())
+	assert.Equal(t, time.Date(2017, 6, 1, 10, 0, 1, 0, time.UTC), w.last.UTC())
+
+	// a resumed stream repeats the last line
+	out.Reset()
+	w.after = w.last
+	w.timestamps = true
+	_, err = w.Write([]byte("2017-06-01T10:00:01.000000000Z second\n2017-06-01T10:00:05.000000000Z third"))
+	require.NoError(t, err)
+	w.flush()
+	assert.Equal(t, "2017-06-01T10:00:05.000000000Z third", out.String())
+}
+
+func TestFollowContainerLogsAcrossReplacement(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventq := make(chan events.Message, 4)
+	eventq <- events.Message{Status: "destroy", Actor: events.Actor{ID: "id1", Attributes: map[string]string{"name": "web"}}}
+	eventq <- events.Message{Status: "create", Actor: events.Actor{ID: "id2", Attributes: map[string]string{"name": "web"}}}
+	eventq <- events.Message{Status: "start", Actor: events.Actor{ID: "id3", Attributes: map[string]string{"name": "other"}}}
+	eventq <- events.Message{Status: "start", Actor: events.Actor{ID: "id2", Attributes: map[string]string{"name": "web"}}}
+
+	logs := map[string]string{
+		"id1": "2017-06-01T10:00:00.000000000Z first\n",
+		"id2": "2017-06-01T10:00:05.000000000Z second\n",
+	}
+	var streamed []string
+	client := &fakeClient{
+		containerInspectFunc: func(ref string) (types.ContainerJSON, error) {
+			id := map[string]string{"web": "id1", "id2": "id2"}[ref]
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: id, Name: "/web"},
+				Config:            &container.Config{Tty: true},
+			}, nil
+		},
+		eventsFunc: func(options types.EventsOptions) (<-chan events.Message, <-chan error) {
+			return eventq, make(chan error)
+		},
+		containerLogsFunc: func(container string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			streamed = append(streamed, container)
+			if container == "id2" {
+				// the follower waits for another start once the logs end
+				cancel()
+			}
+			return ioutil.NopCloser(strings.NewReader(logs[container])), nil
+		},
+	}
+	out := new(bytes.Buffer)
+	cli := test.NewFakeCli(client, out)
+
+	err := followContainerLogs(ctx, cli, &logsOptions{containers: []string{"web"}, tail: "all"})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"id1", "id2"}, streamed)
+	assert.Equal(t, "first\nsecond\n", out.String())
+}
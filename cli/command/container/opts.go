@@ -118,6 +118,8 @@ type containerOptions struct {
 	runtime            string
 	autoRemove         bool
 	init               bool
+	profile            string
+	dotenv             bool
 
 	Image string
 	Args  []string
@@ -166,12 +168,14 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.Var(&copts.devices, "device", "Add a host device to the container")
 	flags.VarP(&copts.env, "env", "e", "Set environment variables")
 	flags.Var(&copts.envFile, "env-file", "Read in a file of environment variables")
+	flags.BoolVar(&copts.dotenv, "dotenv", false, "Parse the --env-file and --label-file files with dotenv rules (quotes, export, variable references)")
 	flags.StringVar(&copts.entrypoint, "entrypoint", "", "Overwrite the default ENTRYPOINT of the image")
 	flags.Var(&copts.groupAdd, "group-add", "Add additional groups to join")
 	flags.StringVarP(&copts.hostname, "hostname", "h", "", "Container host name")
 	flags.BoolVarP(&copts.stdin, "interactive", "i", false, "Keep STDIN open even if not attached")
 	flags.VarP(&copts.labels, "label", "l", "Set meta data on a container")
 	flags.Var(&copts.labelsFile, "label-file", "Read in a line delimited file of labels")
+	flags.StringVar(&copts.profile, "profile", "", "Read the container settings from a JSON or YAML file, overridden by the other flags")
 	flags.BoolVar(&copts.readonlyRootfs, "read-only", false, "Mount the container's root filesystem as read only")
 	flags.StringVar(&copts.restartPolicy, "restart", "no", "Restart policy to apply when a container exits")
 	flags.StringVar(&copts.stopSignal, "stop-signal", signal.DefaultStopSignal, "Signal to stop a container")
@@ -408,14 +412,21 @@ func parse(flags *pflag.FlagSet, copts *containerOptions) (*containerConfig, err
 		deviceMappings = append(deviceMappings, deviceMapping)
 	}
 
+	readKVStrings := func(kind string, files, override []string) ([]string, error) {
+		if copts.dotenv {
+			return opts.ReadKVDotEnvStrings(kind, files, override)
+		}
+		return runconfigopts.ReadKVStrings(files, override)
+	}
+
 	// collect all the environment variables for the container
-	envVariables, err := runconfigopts.ReadKVStrings(copts.envFile.GetAll(), copts.env.GetAll())
+	envVariables, err := readKVStrings("env", copts.envFile.GetAll(), copts.env.GetAll())
 	if err != nil {
 		return nil, err
 	}
 
 	// collect all the labels for the container
-	labels, err := runconfigopts.ReadKVStrings(copts.labelsFile.GetAll(), copts.labels.GetAll())
+	labels, err := readKVStrings("label", copts.labelsFile.GetAll(), copts.labels.GetAll())
 	if err != nil {
 		return nil, err
 	}
@@ -658,11 +669,15 @@ func parse(flags *pflag.FlagSet, copts *containerOptions) (*containerConfig, err
 		networkingConfig.EndpointsConfig[string(hostConfig.NetworkMode)] = epConfig
 	}
 
-	return &containerConfig{
+	flagConfig := &containerConfig{
 		Config:           config,
 		HostConfig:       hostConfig,
 		NetworkingConfig: networkingConfig,
-	}, nil
+	}
+	if copts.profile != "" {
+		return applyProfile(flags, copts, flagConfig)
+	}
+	return flagConfig, nil
 }
 
 func parseLoggingOpts(loggingDriver string, loggingOpts []string) (map[string]string, error) {
@@ -19,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateAttach(t *testing.T) {
@@ -873,3 +874,27 @@ func TestValidateDevice(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDotEnvFile(t *testing.T) {
+	config, _, _, err := parseRun([]string{"--dotenv", "--env-file=testdata/dotenv.env", "-e", "PORT=5433", "img", "cmd"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"HOST=db.local",
+		"PORT=5432",
+		"URL=postgres://db.local:5432/app",
+		"GREETING=hello # not a comment",
+		"PORT=5433",
+	}, config.Env)
+
+	_, _, _, err = parseRun([]string{"--env-file=testdata/dotenv.env", "img", "cmd"})
+	assert.EqualError(t, err, "poorly formatted environment: variable 'export HOST' has white spaces")
+
+	labelFile, err := ioutil.TempFile("", "labels")
+	require.NoError(t, err)
+	defer os.Remove(labelFile.Name())
+	_, err = labelFile.WriteString("team=web\nBROKEN value\n")
+	require.NoError(t, err)
+	require.NoError(t, labelFile.Close())
+	_, _, _, err = parseRun([]string{"--dotenv", "--label-file", labelFile.Name(), "img", "cmd"})
+	assert.EqualError(t, err, "label file "+labelFile.Name()+`: line 2: missing '=' after variable name "BROKEN"`)
+}
@@ -4,38 +4,51 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
 type pauseOptions struct {
+	filter opts.FilterOpt
+	force  bool
+
 	containers []string
 }
 
 // NewPauseCommand creates a new cobra.Command for `docker pause`
 func NewPauseCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts pauseOptions
+	opts := pauseOptions{filter: opts.NewFilterOpt()}
 
-	return &cobra.Command{
-		Use:   "pause CONTAINER [CONTAINER...]",
+	cmd := &cobra.Command{
+		Use:   "pause [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Pause all processes within one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runPause(dockerCli, &opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.Var(&opts.filter, "filter", "Filter the containers to pause, as with 'docker ps --filter'")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
+	return cmd
 }
 
 func runPause(dockerCli *command.DockerCli, opts *pauseOptions) error {
 	ctx := context.Background()
 
 	var errs []string
-	errChan := parallelOperation(ctx, opts.containers, dockerCli.Client().ContainerPause)
-	for _, container := range opts.containers {
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "paused", false, opts.force)
+	if err != nil {
+		return err
+	}
+
+	errChan := parallelOperation(ctx, containers, dockerCli.Client().ContainerPause)
+	for _, container := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
@@ -0,0 +1,612 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/docker/api/types/blkiodev"
+	"github.com/docker/docker/api/types/container"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// requiresImageOrProfile checks that the image is given as an argument,
+// unless the settings of the container are read with --profile.
+func requiresImageOrProfile(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("profile") {
+		return nil
+	}
+	return cli.RequiresMinArgs(1)(cmd, args)
+}
+
+// loadProfile reads the settings of a container from a JSON or YAML file,
+// using the same field names as the Config, HostConfig and NetworkingConfig
+// objects of the container create API.
+func loadProfile(path string) (*containerConfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read profile")
+	}
+	profile, err := parseProfile(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse profile %s", path)
+	}
+	return profile, nil
+}
+
+func parseProfile(content []byte) (*containerConfig, error) {
+	profile := &containerConfig{}
+
+	content = bytes.TrimSpace(content)
+	if !bytes.HasPrefix(content, []byte("{")) {
+		// The API types only carry JSON tags, so YAML is converted to JSON
+		// before being decoded.
+		var raw interface{}
+		if err := yaml.Unmarshal(content, &raw); err != nil {
+			return nil, err
+		}
+		if _, ok := raw.(map[interface{}]interface{}); !ok {
+			return nil, errors.New("top-level object must be a mapping")
+		}
+		converted, err := loader.ConvertToStringKeys(raw)
+		if err != nil {
+			return nil, err
+		}
+		if content, err = json.Marshal(converted); err != nil {
+			return nil, err
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(profile); err != nil {
+		return nil, err
+	}
+
+	if profile.Config == nil {
+		profile.Config = &container.Config{}
+	}
+	if profile.HostConfig == nil {
+		profile.HostConfig = &container.HostConfig{}
+	}
+	if profile.NetworkingConfig == nil {
+		profile.NetworkingConfig = &networktypes.NetworkingConfig{}
+	}
+	if profile.NetworkingConfig.EndpointsConfig == nil {
+		profile.NetworkingConfig.EndpointsConfig = make(map[string]*networktypes.EndpointSettings)
+	}
+	return profile, nil
+}
+
+// applyProfile loads the profile of copts and copies onto it the fields set
+// by the flags of flagConfig, so that the flags override the values in the
+// file. The attach settings always come from the flags, as they describe
+// the session rather than the container.
+func applyProfile(flags *pflag.FlagSet, copts *containerOptions, flagConfig *containerConfig) (*containerConfig, error) {
+	profile, err := loadProfile(copts.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	flags.Visit(func(flag *pflag.Flag) {
+		if override, ok := profileFlagOverrides[flag.Name]; ok {
+			override(profile, flagConfig)
+		}
+	})
+
+	config, hostConfig := profile.Config, profile.HostConfig
+	if copts.Image != "" {
+		config.Image = copts.Image
+	}
+	if len(copts.Args) > 0 {
+		config.Cmd = flagConfig.Config.Cmd
+	}
+	if config.Image == "" {
+		return nil, errors.New("no image specified: pass an IMAGE argument or set Config.Image in the --profile file")
+	}
+
+	config.AttachStdin = flagConfig.Config.AttachStdin
+	if !flags.Changed("attach") {
+		config.AttachStdin = config.OpenStdin
+	}
+	config.AttachStdout = flagConfig.Config.AttachStdout
+	config.AttachStderr = flagConfig.Config.AttachStderr
+	config.StdinOnce = config.OpenStdin && config.AttachStdin
+
+	// Published ports are exposed as well.
+	for port := range hostConfig.PortBindings {
+		if config.ExposedPorts == nil {
+			config.ExposedPorts = nat.PortSet{}
+		}
+		config.ExposedPorts[port] = struct{}{}
+	}
+	if hostConfig.DNS == nil {
+		hostConfig.DNS = []string{}
+	}
+	if hostConfig.DNSSearch == nil {
+		hostConfig.DNSSearch = []string{}
+	}
+	if hostConfig.DNSOptions == nil {
+		hostConfig.DNSOptions = []string{}
+	}
+
+	if hostConfig.AutoRemove && !hostConfig.RestartPolicy.IsNone() {
+		return nil, errors.Errorf("Conflicting options: --restart and --rm")
+	}
+	// The removal of the container is waited for by `docker run`.
+	copts.autoRemove = hostConfig.AutoRemove
+	return profile, nil
+}
+
+type profileOverride func(profile, flagConfig *containerConfig)
+
+// profileFlagOverrides maps the flags of "run" and "create" to the fields of
+// the container settings they set. The lists and maps of the profile are
+// merged with the values of the flags, which win for the same key.
+var profileFlagOverrides = map[string]profileOverride{
+	"interactive": func(profile, flagConfig *containerConfig) {
+		profile.Config.OpenStdin = flagConfig.Config.OpenStdin
+	},
+	"env":      overrideEnv,
+	"env-file": overrideEnv,
+	"entrypoint": func(profile, flagConfig *containerConfig) {
+		profile.Config.Entrypoint = flagConfig.Config.Entrypoint
+	},
+	"hostname": func(profile, flagConfig *containerConfig) {
+		profile.Config.Hostname = flagConfig.Config.Hostname
+	},
+	"label":      overrideLabels,
+	"label-file": overrideLabels,
+	"stop-signal": func(profile, flagConfig *containerConfig) {
+		profile.Config.StopSignal = flagConfig.Config.StopSignal
+	},
+	"stop-timeout": func(profile, flagConfig *containerConfig) {
+		profile.Config.StopTimeout = flagConfig.Config.StopTimeout
+	},
+	"tty": func(profile, flagConfig *containerConfig) {
+		profile.Config.Tty = flagConfig.Config.Tty
+	},
+	"user": func(profile, flagConfig *containerConfig) {
+		profile.Config.User = flagConfig.Config.User
+	},
+	"workdir": func(profile, flagConfig *containerConfig) {
+		profile.Config.WorkingDir = flagConfig.Config.WorkingDir
+	},
+	"mac-address": func(profile, flagConfig *containerConfig) {
+		profile.Config.MacAddress = flagConfig.Config.MacAddress
+	},
+	"expose": overrideExposedPorts,
+	"publish": func(profile, flagConfig *containerConfig) {
+		overrideExposedPorts(profile, flagConfig)
+		if profile.HostConfig.PortBindings == nil {
+			profile.HostConfig.PortBindings = nat.PortMap{}
+		}
+		for port, bindings := range flagConfig.HostConfig.PortBindings {
+			profile.HostConfig.PortBindings[port] = bindings
+		}
+	},
+	"volume": func(profile, flagConfig *containerConfig) {
+		if profile.Config.Volumes == nil {
+			profile.Config.Volumes = map[string]struct{}{}
+		}
+		for volume := range flagConfig.Config.Volumes {
+			profile.Config.Volumes[volume] = struct{}{}
+		}
+		profile.HostConfig.Binds = mergeByKey(profile.HostConfig.Binds, flagConfig.HostConfig.Binds, bindDestination)
+	},
+	"health-cmd": func(profile, flagConfig *containerConfig) {
+		profileHealthcheck(profile).Test = flagConfig.Config.Healthcheck.Test
+	},
+	"health-interval": func(profile, flagConfig *containerConfig) {
+		profileHealthcheck(profile).Interval = flagConfig.Config.Healthcheck.Interval
+	},
+	"health-retries": func(profile, flagConfig *containerConfig) {
+		profileHealthcheck(profile).Retries = flagConfig.Config.Healthcheck.Retries
+	},
+	"health-timeout": func(profile, flagConfig *containerConfig) {
+		profileHealthcheck(profile).Timeout = flagConfig.Config.Healthcheck.Timeout
+	},
+	"health-start-period": func(profile, flagConfig *containerConfig) {
+		profileHealthcheck(profile).StartPeriod = flagConfig.Config.Healthcheck.StartPeriod
+	},
+	"no-healthcheck": func(profile, flagConfig *containerConfig) {
+		profile.Config.Healthcheck = flagConfig.Config.Healthcheck
+	},
+
+	"device": func(profile, flagConfig *containerConfig) {
+		devices := profile.HostConfig.Devices
+		for _, device := range flagConfig.HostConfig.Devices {
+			replaced := false
+			for i := range devices {
+				if devices[i].PathInContainer == device.PathInContainer {
+					devices[i], replaced = device, true
+				}
+			}
+			if !replaced {
+				devices = append(devices, device)
+			}
+		}
+		profile.HostConfig.Devices = devices
+	},
+	"device-cgroup-rule": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.DeviceCgroupRules = appendUnique(profile.HostConfig.DeviceCgroupRules, flagConfig.HostConfig.DeviceCgroupRules)
+	},
+	"group-add": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.GroupAdd = appendUnique(profile.HostConfig.GroupAdd, flagConfig.HostConfig.GroupAdd)
+	},
+	"read-only": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.ReadonlyRootfs = flagConfig.HostConfig.ReadonlyRootfs
+	},
+	"restart": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.RestartPolicy = flagConfig.HostConfig.RestartPolicy
+	},
+	"rm": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.AutoRemove = flagConfig.HostConfig.AutoRemove
+	},
+	"sysctl": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Sysctls = mergeMap(profile.HostConfig.Sysctls, flagConfig.HostConfig.Sysctls)
+	},
+	"ulimit": func(profile, flagConfig *containerConfig) {
+		ulimits := profile.HostConfig.Ulimits
+		for _, ulimit := range flagConfig.HostConfig.Ulimits {
+			replaced := false
+			for i := range ulimits {
+				if ulimits[i].Name == ulimit.Name {
+					ulimits[i], replaced = ulimit, true
+				}
+			}
+			if !replaced {
+				ulimits = append(ulimits, ulimit)
+			}
+		}
+		profile.HostConfig.Ulimits = ulimits
+	},
+	"cap-add": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CapAdd = appendUnique(profile.HostConfig.CapAdd, flagConfig.HostConfig.CapAdd)
+	},
+	"cap-drop": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CapDrop = appendUnique(profile.HostConfig.CapDrop, flagConfig.HostConfig.CapDrop)
+	},
+	"privileged": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Privileged = flagConfig.HostConfig.Privileged
+	},
+	"security-opt": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.SecurityOpt = appendUnique(profile.HostConfig.SecurityOpt, flagConfig.HostConfig.SecurityOpt)
+	},
+	"userns": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.UsernsMode = flagConfig.HostConfig.UsernsMode
+	},
+	"add-host": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.ExtraHosts = mergeByKey(profile.HostConfig.ExtraHosts, flagConfig.HostConfig.ExtraHosts, func(host string) string {
+			return strings.SplitN(host, ":", 2)[0]
+		})
+	},
+	"dns": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.DNS = appendUnique(profile.HostConfig.DNS, flagConfig.HostConfig.DNS)
+	},
+	"dns-opt":    overrideDNSOptions,
+	"dns-option": overrideDNSOptions,
+	"dns-search": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.DNSSearch = appendUnique(profile.HostConfig.DNSSearch, flagConfig.HostConfig.DNSSearch)
+	},
+	"link": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Links = appendUnique(profile.HostConfig.Links, flagConfig.HostConfig.Links)
+		overrideEndpoint(profile, flagConfig, func(endpoint, flagEndpoint *networktypes.EndpointSettings) {
+			endpoint.Links = appendUnique(endpoint.Links, flagEndpoint.Links)
+		})
+	},
+	"publish-all": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.PublishAllPorts = flagConfig.HostConfig.PublishAllPorts
+	},
+	"net":     overrideNetworkMode,
+	"network": overrideNetworkMode,
+	"ip": func(profile, flagConfig *containerConfig) {
+		overrideIPAMConfig(profile, flagConfig, func(config, flagIPAMConfig *networktypes.EndpointIPAMConfig) {
+			config.IPv4Address = flagIPAMConfig.IPv4Address
+		})
+	},
+	"ip6": func(profile, flagConfig *containerConfig) {
+		overrideIPAMConfig(profile, flagConfig, func(config, flagIPAMConfig *networktypes.EndpointIPAMConfig) {
+			config.IPv6Address = flagIPAMConfig.IPv6Address
+		})
+	},
+	"link-local-ip": func(profile, flagConfig *containerConfig) {
+		overrideIPAMConfig(profile, flagConfig, func(config, flagIPAMConfig *networktypes.EndpointIPAMConfig) {
+			config.LinkLocalIPs = appendUnique(config.LinkLocalIPs, flagIPAMConfig.LinkLocalIPs)
+		})
+	},
+	"net-alias":     overrideAliases,
+	"network-alias": overrideAliases,
+	"log-driver": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.LogConfig.Type = flagConfig.HostConfig.LogConfig.Type
+	},
+	"log-opt": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.LogConfig.Config = mergeMap(profile.HostConfig.LogConfig.Config, flagConfig.HostConfig.LogConfig.Config)
+	},
+	"volume-driver": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.VolumeDriver = flagConfig.HostConfig.VolumeDriver
+	},
+	"storage-opt": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.StorageOpt = mergeMap(profile.HostConfig.StorageOpt, flagConfig.HostConfig.StorageOpt)
+	},
+	"tmpfs": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Tmpfs = mergeMap(profile.HostConfig.Tmpfs, flagConfig.HostConfig.Tmpfs)
+	},
+	"volumes-from": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.VolumesFrom = appendUnique(profile.HostConfig.VolumesFrom, flagConfig.HostConfig.VolumesFrom)
+	},
+	"mount": func(profile, flagConfig *containerConfig) {
+		mounts := profile.HostConfig.Mounts
+		for _, m := range flagConfig.HostConfig.Mounts {
+			replaced := false
+			for i := range mounts {
+				if mounts[i].Target == m.Target {
+					mounts[i], replaced = m, true
+				}
+			}
+			if !replaced {
+				mounts = append(mounts, m)
+			}
+		}
+		profile.HostConfig.Mounts = mounts
+	},
+	"cidfile": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.ContainerIDFile = flagConfig.HostConfig.ContainerIDFile
+	},
+	"oom-score-adj": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.OomScoreAdj = flagConfig.HostConfig.OomScoreAdj
+	},
+	"ipc": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.IpcMode = flagConfig.HostConfig.IpcMode
+	},
+	"isolation": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Isolation = flagConfig.HostConfig.Isolation
+	},
+	"pid": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.PidMode = flagConfig.HostConfig.PidMode
+	},
+	"shm-size": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.ShmSize = flagConfig.HostConfig.ShmSize
+	},
+	"uts": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.UTSMode = flagConfig.HostConfig.UTSMode
+	},
+	"runtime": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Runtime = flagConfig.HostConfig.Runtime
+	},
+	"init": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Init = flagConfig.HostConfig.Init
+	},
+
+	"blkio-weight": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.BlkioWeight = flagConfig.HostConfig.BlkioWeight
+	},
+	"blkio-weight-device": func(profile, flagConfig *containerConfig) {
+		devices := profile.HostConfig.BlkioWeightDevice
+		for _, device := range flagConfig.HostConfig.BlkioWeightDevice {
+			replaced := false
+			for i := range devices {
+				if devices[i].Path == device.Path {
+					devices[i], replaced = device, true
+				}
+			}
+			if !replaced {
+				devices = append(devices, device)
+			}
+		}
+		profile.HostConfig.BlkioWeightDevice = devices
+	},
+	"device-read-bps": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.BlkioDeviceReadBps = mergeThrottleDevices(profile.HostConfig.BlkioDeviceReadBps, flagConfig.HostConfig.BlkioDeviceReadBps)
+	},
+	"device-read-iops": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.BlkioDeviceReadIOps = mergeThrottleDevices(profile.HostConfig.BlkioDeviceReadIOps, flagConfig.HostConfig.BlkioDeviceReadIOps)
+	},
+	"device-write-bps": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.BlkioDeviceWriteBps = mergeThrottleDevices(profile.HostConfig.BlkioDeviceWriteBps, flagConfig.HostConfig.BlkioDeviceWriteBps)
+	},
+	"device-write-iops": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.BlkioDeviceWriteIOps = mergeThrottleDevices(profile.HostConfig.BlkioDeviceWriteIOps, flagConfig.HostConfig.BlkioDeviceWriteIOps)
+	},
+	"cgroup-parent": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CgroupParent = flagConfig.HostConfig.CgroupParent
+	},
+	"cpu-count": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPUCount = flagConfig.HostConfig.CPUCount
+	},
+	"cpu-percent": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPUPercent = flagConfig.HostConfig.CPUPercent
+	},
+	"cpu-period": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPUPeriod = flagConfig.HostConfig.CPUPeriod
+	},
+	"cpu-quota": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPUQuota = flagConfig.HostConfig.CPUQuota
+	},
+	"cpu-rt-period": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPURealtimePeriod = flagConfig.HostConfig.CPURealtimePeriod
+	},
+	"cpu-rt-runtime": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPURealtimeRuntime = flagConfig.HostConfig.CPURealtimeRuntime
+	},
+	"cpu-shares": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CPUShares = flagConfig.HostConfig.CPUShares
+	},
+	"cpus": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.NanoCPUs = flagConfig.HostConfig.NanoCPUs
+	},
+	"cpuset-cpus": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CpusetCpus = flagConfig.HostConfig.CpusetCpus
+	},
+	"cpuset-mems": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.CpusetMems = flagConfig.HostConfig.CpusetMems
+	},
+	"io-maxbandwidth": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.IOMaximumBandwidth = flagConfig.HostConfig.IOMaximumBandwidth
+	},
+	"io-maxiops": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.IOMaximumIOps = flagConfig.HostConfig.IOMaximumIOps
+	},
+	"kernel-memory": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.KernelMemory = flagConfig.HostConfig.KernelMemory
+	},
+	"memory": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.Memory = flagConfig.HostConfig.Memory
+	},
+	"memory-reservation": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.MemoryReservation = flagConfig.HostConfig.MemoryReservation
+	},
+	"memory-swap": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.MemorySwap = flagConfig.HostConfig.MemorySwap
+	},
+	"memory-swappiness": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.MemorySwappiness = flagConfig.HostConfig.MemorySwappiness
+	},
+	"oom-kill-disable": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.OomKillDisable = flagConfig.HostConfig.OomKillDisable
+	},
+	"pids-limit": func(profile, flagConfig *containerConfig) {
+		profile.HostConfig.PidsLimit = flagConfig.HostConfig.PidsLimit
+	},
+}
+
+// overrideEnv sets the variables given by flags, keeping the other variables
+// of the profile.
+func overrideEnv(profile, flagConfig *containerConfig) {
+	profile.Config.Env = mergeByKey(profile.Config.Env, flagConfig.Config.Env, func(variable string) string {
+		return strings.SplitN(variable, "=", 2)[0]
+	})
+}
+
+// overrideLabels sets the labels given by flags, keeping the other labels of
+// the profile.
+func overrideLabels(profile, flagConfig *containerConfig) {
+	profile.Config.Labels = mergeMap(profile.Config.Labels, flagConfig.Config.Labels)
+}
+
+func overrideExposedPorts(profile, flagConfig *containerConfig) {
+	if profile.Config.ExposedPorts == nil {
+		profile.Config.ExposedPorts = nat.PortSet{}
+	}
+	for port := range flagConfig.Config.ExposedPorts {
+		profile.Config.ExposedPorts[port] = struct{}{}
+	}
+}
+
+// profileHealthcheck returns the healthcheck of the profile, which is created
+// if needed. A disabled healthcheck is reset, as the flags enable it.
+func profileHealthcheck(profile *containerConfig) *container.HealthConfig {
+	healthcheck := profile.Config.Healthcheck
+	if healthcheck == nil || len(healthcheck.Test) > 0 && healthcheck.Test[0] == "NONE" {
+		healthcheck = &container.HealthConfig{}
+		profile.Config.Healthcheck = healthcheck
+	}
+	return healthcheck
+}
+
+func overrideDNSOptions(profile, flagConfig *containerConfig) {
+	profile.HostConfig.DNSOptions = appendUnique(profile.HostConfig.DNSOptions, flagConfig.HostConfig.DNSOptions)
+}
+
+func overrideNetworkMode(profile, flagConfig *containerConfig) {
+	profile.HostConfig.NetworkMode = flagConfig.HostConfig.NetworkMode
+}
+
+func overrideIPAMConfig(profile, flagConfig *containerConfig, override func(config, flagIPAMConfig *networktypes.EndpointIPAMConfig)) {
+	overrideEndpoint(profile, flagConfig, func(endpoint, flagEndpoint *networktypes.EndpointSettings) {
+		if flagEndpoint.IPAMConfig == nil {
+			return
+		}
+		if endpoint.IPAMConfig == nil {
+			endpoint.IPAMConfig = &networktypes.EndpointIPAMConfig{}
+		}
+		override(endpoint.IPAMConfig, flagEndpoint.IPAMConfig)
+	})
+}
+
+func overrideAliases(profile, flagConfig *containerConfig) {
+	overrideEndpoint(profile, flagConfig, func(endpoint, flagEndpoint *networktypes.EndpointSettings) {
+		endpoint.Aliases = appendUnique(endpoint.Aliases, flagEndpoint.Aliases)
+	})
+}
+
+// overrideEndpoint copies fields of the endpoint settings given by flags,
+// which apply to the network set with --network.
+func overrideEndpoint(profile, flagConfig *containerConfig, override func(endpoint, flagEndpoint *networktypes.EndpointSettings)) {
+	networkName := string(flagConfig.HostConfig.NetworkMode)
+	flagEndpoint := flagConfig.NetworkingConfig.EndpointsConfig[networkName]
+	if flagEndpoint == nil {
+		return
+	}
+	endpoint := profile.NetworkingConfig.EndpointsConfig[networkName]
+	if endpoint == nil {
+		endpoint = &networktypes.EndpointSettings{}
+		profile.NetworkingConfig.EndpointsConfig[networkName] = endpoint
+	}
+	override(endpoint, flagEndpoint)
+}
+
+// mergeMap sets the entries of values in m, which is created if needed.
+func mergeMap(m, values map[string]string) map[string]string {
+	if m == nil {
+		m = map[string]string{}
+	}
+	for key, value := range values {
+		m[key] = value
+	}
+	return m
+}
+
+// mergeByKey replaces the elements of list which have the key of an element
+// of values, and appends the other values.
+func mergeByKey(list, values []string, key func(string) string) []string {
+	merged := append([]string{}, list...)
+	for _, value := range values {
+		replaced := false
+		for i := range merged {
+			if key(merged[i]) == key(value) {
+				merged[i], replaced = value, true
+			}
+		}
+		if !replaced {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// appendUnique appends the values which are not in list yet.
+func appendUnique(list, values []string) []string {
+	return mergeByKey(list, values, func(value string) string { return value })
+}
+
+// bindDestination returns the path in the container of a bind, given in the
+// "[source:]destination[:mode]" format of --volume.
+func bindDestination(bind string) string {
+	fields := strings.Split(bind, ":")
+	if len(fields) == 1 {
+		return fields[0]
+	}
+	return fields[1]
+}
+
+func mergeThrottleDevices(devices, values []*blkiodev.ThrottleDevice) []*blkiodev.ThrottleDevice {
+	for _, device := range values {
+		replaced := false
+		for i := range devices {
+			if devices[i].Path == device.Path {
+				devices[i], replaced = device, true
+			}
+		}
+		if !replaced {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
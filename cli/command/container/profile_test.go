@@ -0,0 +1,144 @@
+package container
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProfile = `
+Config:
+  Image: nginx:alpine
+  Cmd: ["nginx", "-g", "daemon off;"]
+  Env:
+    - MODE=production
+  Labels:
+    team: web
+HostConfig:
+  PortBindings:
+    80/tcp:
+      - HostPort: "8080"
+  RestartPolicy:
+    Name: unless-stopped
+  Memory: 536870912
+  CapDrop: [ALL]
+NetworkingConfig:
+  EndpointsConfig:
+    frontend:
+      Aliases: [web]
+`
+
+func writeTestProfile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "profile")
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	return file.Name()
+}
+
+func TestParseRunProfile(t *testing.T) {
+	profile := writeTestProfile(t, testProfile)
+	defer os.Remove(profile)
+
+	config, hostConfig, networkingConfig, err := parseRun([]string{"--profile", profile})
+	require.NoError(t, err)
+
+	assert.Equal(t, "nginx:alpine", config.Image)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, []string(config.Cmd))
+	assert.Equal(t, []string{"MODE=production"}, config.Env)
+	assert.Equal(t, map[string]string{"team": "web"}, config.Labels)
+	assert.Equal(t, nat.PortSet{"80/tcp": {}}, config.ExposedPorts)
+	assert.True(t, config.AttachStdout)
+	assert.Equal(t, "unless-stopped", hostConfig.RestartPolicy.Name)
+	assert.Equal(t, int64(536870912), hostConfig.Memory)
+	assert.Equal(t, []string{"ALL"}, []string(hostConfig.CapDrop))
+	assert.Equal(t, []string{}, hostConfig.DNS)
+	require.Contains(t, networkingConfig.EndpointsConfig, "frontend")
+	assert.Equal(t, []string{"web"}, networkingConfig.EndpointsConfig["frontend"].Aliases)
+}
+
+func TestParseRunProfileFlagsOverride(t *testing.T) {
+	profile := writeTestProfile(t, testProfile)
+	defer os.Remove(profile)
+
+	flags := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	copts := addFlags(flags)
+	require.NoError(t, flags.Parse([]string{
+		"--profile", profile,
+		"-e", "MODE=debug",
+		"--memory", "1g",
+		"--network", "frontend",
+		"--network-alias", "debug",
+	}))
+	copts.Image = "nginx:latest"
+	copts.Args = []string{"sh"}
+	containerConfig, err := parse(flags, copts)
+	require.NoError(t, err)
+	config, hostConfig, networkingConfig := containerConfig.Config, containerConfig.HostConfig, containerConfig.NetworkingConfig
+
+	assert.Equal(t, "nginx:latest", config.Image)
+	assert.Equal(t, []string{"sh"}, []string(config.Cmd))
+	assert.Equal(t, []string{"MODE=debug"}, config.Env)
+	assert.Equal(t, map[string]string{"team": "web"}, config.Labels)
+	assert.Equal(t, int64(1024*1024*1024), hostConfig.Memory)
+	assert.Equal(t, container.NetworkMode("frontend"), hostConfig.NetworkMode)
+	assert.Equal(t, "unless-stopped", hostConfig.RestartPolicy.Name)
+	assert.Equal(t, []string{"web", "debug"}, networkingConfig.EndpointsConfig["frontend"].Aliases)
+}
+
+func TestParseRunProfileFlagsMerge(t *testing.T) {
+	profile := writeTestProfile(t, testProfile)
+	defer os.Remove(profile)
+
+	config, _, _, err := parseRun([]string{
+		"--profile", profile,
+		"-e", "DEBUG=1",
+		"--label", "tier=front",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MODE=production", "DEBUG=1"}, config.Env)
+	assert.Equal(t, map[string]string{"team": "web", "tier": "front"}, config.Labels)
+}
+
+func TestParseRunProfileErrors(t *testing.T) {
+	noImage := writeTestProfile(t, "HostConfig:\n  Privileged: true\n")
+	defer os.Remove(noImage)
+	_, _, _, err := parseRun([]string{"--profile", noImage})
+	assert.EqualError(t, err, "no image specified: pass an IMAGE argument or set Config.Image in the --profile file")
+
+	notMapping := writeTestProfile(t, "- Image: nginx\n")
+	defer os.Remove(notMapping)
+	_, _, _, err = parseRun([]string{"--profile", notMapping})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "top-level object must be a mapping")
+
+	_, _, _, err = parseRun([]string{"--profile", "/does/not/exist.yaml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read profile")
+}
+
+func TestParseProfileJSON(t *testing.T) {
+	profile, err := parseProfile([]byte(`{"Config": {"Image": "busybox"}, "HostConfig": {"ReadonlyRootfs": true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "busybox", profile.Config.Image)
+	assert.True(t, profile.HostConfig.ReadonlyRootfs)
+	assert.NotNil(t, profile.NetworkingConfig.EndpointsConfig)
+}
+
+func TestParseProfileYAMLEmptyList(t *testing.T) {
+	// an empty entrypoint resets the one of the image, unlike a missing one
+	profile, err := parseProfile([]byte("Config:\n  Image: busybox\n  Entrypoint: []\n"))
+	require.NoError(t, err)
+	assert.NotNil(t, profile.Config.Entrypoint)
+	assert.Len(t, profile.Config.Entrypoint, 0)
+
+	_, err = parseProfile([]byte("Config:\n  Labels:\n    1: one\n"))
+	assert.EqualError(t, err, "Non-string key in Config.Labels: 1")
+}
@@ -0,0 +1,212 @@
+package container
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+)
+
+// asciicastHeader is the first line of a recording in the asciicast v2
+// format, see https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint              `json:"width"`
+	Height    uint              `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event types of asciicast recordings
+const (
+	asciicastOutput = "o"
+	asciicastInput  = "i"
+	asciicastResize = "r"
+)
+
+// sessionRecorder writes the input, output and terminal resizes of an
+// attached session as an asciicast v2 recording. A nil sessionRecorder
+// records nothing.
+type sessionRecorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	start time.Time
+	now   func() time.Time
+	err   error
+	// closed is set by Close, after which no event is recorded
+	closed bool
+}
+
+// newSessionRecorder creates the recording file path for a session in a
+// terminal of the size of the output of dockerCli.
+func newSessionRecorder(dockerCli command.Cli, path, title string) (*sessionRecorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create recording")
+	}
+	height, width := dockerCli.Out().GetTtySize()
+	if height == 0 || width == 0 {
+		height, width = 24, 80
+	}
+	r := &sessionRecorder{file: file, w: bufio.NewWriter(file), now: time.Now}
+	if err := r.writeHeader(width, height, title); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *sessionRecorder) writeHeader(width, height uint, title string) error {
+	r.start = r.now()
+	header := asciicastHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     title,
+	}
+	if term := os.Getenv("TERM"); term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	return r.writeLine(header)
+}
+
+func (r *sessionRecorder) writeLine(value interface{}) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write recording")
+	}
+	return nil
+}
+
+// event records an event of the session. The first error is kept and
+// returned by Close, so that a failing recording does not interrupt the
+// session.
+func (r *sessionRecorder) event(kind, data string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil || r.closed {
+		return
+	}
+	elapsed := r.now().Sub(r.start).Seconds()
+	r.err = r.writeLine([]interface{}{elapsed, kind, data})
+	if r.err == nil {
+		// Flush so that the recording survives the client being killed.
+		r.err = r.w.Flush()
+	}
+}
+
+// resize records a resize of the terminal
+func (r *sessionRecorder) resize(height, width uint) {
+	if height == 0 && width == 0 {
+		return
+	}
+	r.event(asciicastResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// output returns a writer recording what is written to w as output
+func (r *sessionRecorder) output(w io.Writer) io.Writer {
+	if r == nil || w == nil {
+		return w
+	}
+	return &recordWriter{recorder: r, w: w}
+}
+
+// input returns a reader recording what is read from in as input
+func (r *sessionRecorder) input(in io.ReadCloser) io.ReadCloser {
+	if r == nil || in == nil {
+		return in
+	}
+	return &recordReader{ReadCloser: in, recorder: r}
+}
+
+// Close closes the recording, and returns the first error met while
+// recording
+func (r *sessionRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	if err := r.file.Close(); r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// utf8Buffer holds the end of a chunk which splits a multi-byte UTF-8
+// sequence, as events must be valid UTF-8 strings.
+type utf8Buffer struct {
+	pending []byte
+}
+
+// complete returns the pending bytes followed by p, up to the last
+// complete UTF-8 sequence, and keeps the rest pending
+func (b *utf8Buffer) complete(p []byte) string {
+	data := append(b.pending, p...)
+	end := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+	b.pending = append([]byte(nil), data[end:]...)
+	return string(data[:end])
+}
+
+type recordWriter struct {
+	recorder *sessionRecorder
+	w        io.Writer
+	buf      utf8Buffer
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n > 0 {
+		if data := w.buf.complete(p[:n]); data != "" {
+			w.recorder.event(asciicastOutput, data)
+		}
+	}
+	return n, err
+}
+
+type recordReader struct {
+	io.ReadCloser
+	recorder *sessionRecorder
+	buf      utf8Buffer
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if data := r.buf.complete(p[:n]); data != "" {
+			r.recorder.event(asciicastInput, data)
+		}
+	}
+	return n, err
+}
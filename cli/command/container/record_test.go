@@ -0,0 +1,123 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRecorder(t *testing.T) {
+	file, err := ioutil.TempFile("", "recording")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	now := time.Unix(1500000000, 0)
+	r := &sessionRecorder{file: file, w: bufio.NewWriter(file), now: func() time.Time { return now }}
+	defer os.Setenv("TERM", os.Getenv("TERM"))
+	os.Setenv("TERM", "xterm")
+	require.NoError(t, r.writeHeader(100, 30, "docker exec app sh"))
+
+	var out bytes.Buffer
+	w := r.output(&out)
+	in := r.input(ioutil.NopCloser(strings.NewReader("ls\n")))
+
+	now = now.Add(500 * time.Millisecond)
+	buf := make([]byte, 16)
+	n, err := in.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ls\n", string(buf[:n]))
+
+	now = now.Add(250 * time.Millisecond)
+	_, err = w.Write([]byte("caf\xc3"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("\xa9\r\n"))
+	require.NoError(t, err)
+
+	now = now.Add(time.Second)
+	r.resize(40, 120)
+
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+	r.event(asciicastOutput, "after close")
+
+	assert.Equal(t, "caf\xc3\xa9\r\n", out.String())
+	recording, err := ioutil.ReadFile(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":2,"width":100,"height":30,"timestamp":1500000000,"title":"docker exec app sh","env":{"TERM":"xterm"}}
+[0.5,"i","ls\n"]
+[0.75,"o","caf"]
+[0.75,"o","é\r\n"]
+[1.75,"r","120x40"]
+`, string(recording))
+}
+
+func TestNilSessionRecorder(t *testing.T) {
+	var r *sessionRecorder
+	var out bytes.Buffer
+	assert.Equal(t, &out, r.output(&out))
+	assert.Nil(t, r.input(nil))
+	r.resize(24, 80)
+	assert.NoError(t, r.Close())
+}
+
+func TestUTF8BufferComplete(t *testing.T) {
+	var b utf8Buffer
+	assert.Equal(t, "a", b.complete([]byte("a\xe2\x82")))
+	assert.Equal(t, "\xe2\x82\xac", b.complete([]byte("\xac")))
+	assert.Equal(t, "", b.complete([]byte("\xf0\x9f")))
+	assert.Equal(t, "\xf0\x9f\x90\xb3!", b.complete([]byte("\x90\xb3!")))
+	// Invalid bytes are not held back forever.
+	assert.Equal(t, "\xff\xfe", b.complete([]byte("\xff\xfe")))
+}
+
+func TestSessionPlayer(t *testing.T) {
+	recording := `{"version":2,"width":80,"height":24}
+[0.5,"o","$ "]
+[1.0,"i","ls\n"]
+[1.5,"o","ls\r\n"]
+
+[1.5,"r","100x30"]
+[11.5,"o","done\r\n"]
+`
+	var delays []time.Duration
+	player := &sessionPlayer{
+		speed:     2,
+		idleLimit: 3 * time.Second,
+		sleep:     func(d time.Duration) { delays = append(delays, d) },
+	}
+	var out bytes.Buffer
+	require.NoError(t, player.play(strings.NewReader(recording), &out))
+	assert.Equal(t, "$ ls\r\ndone\r\n", out.String())
+	assert.Equal(t, []time.Duration{
+		250 * time.Millisecond,
+		250 * time.Millisecond,
+		250 * time.Millisecond,
+		3 * time.Second,
+	}, delays)
+}
+
+func TestSessionPlayerErrors(t *testing.T) {
+	testCases := []struct {
+		recording string
+		expected  string
+	}{
+		{recording: "", expected: "empty recording"},
+		{recording: "not json\n", expected: "invalid recording header"},
+		{recording: `{"version":1}` + "\n", expected: "unsupported recording version 1: only asciicast v2 recordings can be played"},
+		{recording: `{"version":2}` + "\n" + `[0.1,"o"]` + "\n", expected: "invalid event at line 2 of the recording: an event must have 3 elements"},
+		{recording: `{"version":2}` + "\n" + `[0.1,"o","a"]` + "\n" + `["x","o","b"]` + "\n", expected: "invalid event at line 3 of the recording"},
+	}
+	for _, tc := range testCases {
+		player := &sessionPlayer{speed: 1, sleep: func(time.Duration) {}}
+		err := player.play(strings.NewReader(tc.recording), ioutil.Discard)
+		require.Error(t, err, tc.recording)
+		assert.Contains(t, err.Error(), tc.expected)
+	}
+}
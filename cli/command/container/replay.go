@@ -0,0 +1,137 @@
+package container
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type replayOptions struct {
+	file      string
+	speed     float64
+	idleLimit time.Duration
+}
+
+// NewReplayCommand creates a new cobra.Command for `docker replay`
+func NewReplayCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts replayOptions
+
+	cmd := &cobra.Command{
+		Use:   "replay [OPTIONS] FILE",
+		Short: "Play back a session recorded with --record",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.file = args[0]
+			return runReplay(dockerCli, &opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Float64Var(&opts.speed, "speed", 1, "Playback speed factor")
+	flags.DurationVar(&opts.idleLimit, "idle-time-limit", 0, "Limit the pauses of the playback to a duration (0 for no limit)")
+
+	return cmd
+}
+
+func runReplay(dockerCli *command.DockerCli, opts *replayOptions) error {
+	if opts.speed <= 0 {
+		return errors.Errorf("invalid speed %v: must be greater than 0", opts.speed)
+	}
+	file, err := os.Open(opts.file)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	player := &sessionPlayer{speed: opts.speed, idleLimit: opts.idleLimit, sleep: time.Sleep}
+	return player.play(file, dockerCli.Out())
+}
+
+// sessionPlayer writes the output of an asciicast recording with the timing
+// of the recorded session
+type sessionPlayer struct {
+	speed     float64
+	idleLimit time.Duration
+	sleep     func(time.Duration)
+}
+
+func (p *sessionPlayer) play(recording io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(recording)
+	// Output events may hold large chunks of output.
+	scanner.Buffer(nil, 16*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("empty recording")
+	}
+	var header asciicastHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return errors.Wrap(err, "invalid recording header")
+	}
+	if header.Version != 2 {
+		return errors.Errorf("unsupported recording version %d: only asciicast v2 recordings can be played", header.Version)
+	}
+
+	var elapsed float64
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var (
+			event     []json.RawMessage
+			timestamp float64
+			kind      string
+			data      string
+		)
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err == nil && len(event) != 3 {
+			err = errors.New("an event must have 3 elements")
+		}
+		if err == nil {
+			err = json.Unmarshal(event[0], &timestamp)
+		}
+		if err == nil {
+			err = json.Unmarshal(event[1], &kind)
+		}
+		if err == nil {
+			err = json.Unmarshal(event[2], &data)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "invalid event at line %d of the recording", line)
+		}
+
+		if delay := p.delay(timestamp - elapsed); delay > 0 {
+			p.sleep(delay)
+		}
+		elapsed = timestamp
+
+		// Input is echoed in the output by the terminal, and the terminal
+		// of the playback can not be resized.
+		if kind == asciicastOutput {
+			if _, err := fmt.Fprint(out, data); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// delay returns how long to wait for an event recorded seconds after the
+// previous one
+func (p *sessionPlayer) delay(seconds float64) time.Duration {
+	delay := time.Duration(seconds / p.speed * float64(time.Second))
+	if p.idleLimit > 0 && delay > p.idleLimit {
+		return p.idleLimit
+	}
+	return delay
+}
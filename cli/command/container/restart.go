@@ -5,8 +5,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -15,18 +15,20 @@ import (
 type restartOptions struct {
 	nSeconds        int
 	nSecondsChanged bool
+	filter          opts.FilterOpt
+	force           bool
 
 	containers []string
 }
 
 // NewRestartCommand creates a new cobra.Command for `docker restart`
 func NewRestartCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts restartOptions
+	opts := restartOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "restart [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Restart one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			opts.nSecondsChanged = cmd.Flags().Changed("time")
@@ -36,6 +38,8 @@ func NewRestartCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.nSeconds, "time", "t", 10, "Seconds to wait for stop before killing the container")
+	flags.Var(&opts.filter, "filter", "Filter the containers to restart, as with 'docker ps --filter'")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
 	return cmd
 }
 
@@ -48,8 +52,16 @@ func runRestart(dockerCli *command.DockerCli, opts *restartOptions) error {
 		timeout = &timeoutValue
 	}
 
-	for _, name := range opts.containers {
-		if err := dockerCli.Client().ContainerRestart(ctx, name, timeout); err != nil {
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "restarted", false, opts.force)
+	if err != nil {
+		return err
+	}
+
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
+		return dockerCli.Client().ContainerRestart(ctx, container, timeout)
+	})
+	for _, name := range containers {
+		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
 		}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -16,18 +16,20 @@ type rmOptions struct {
 	rmVolumes bool
 	rmLink    bool
 	force     bool
+	yes       bool
+	filter    opts.FilterOpt
 
 	containers []string
 }
 
 // NewRmCommand creates a new cobra.Command for `docker rm`
 func NewRmCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts rmOptions
+	opts := rmOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "rm [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Remove one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runRm(dockerCli, &opts)
@@ -38,6 +40,8 @@ func NewRmCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVarP(&opts.rmVolumes, "volumes", "v", false, "Remove the volumes associated with the container")
 	flags.BoolVarP(&opts.rmLink, "link", "l", false, "Remove the specified link")
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of a running container (uses SIGKILL)")
+	flags.Var(&opts.filter, "filter", "Filter the containers to remove, as with 'docker ps --filter'")
+	flags.BoolVar(&opts.yes, "yes", false, "Do not prompt for confirmation of the containers matching the filter")
 	return cmd
 }
 
@@ -51,7 +55,12 @@ func runRm(dockerCli *command.DockerCli, opts *rmOptions) error {
 		Force:         opts.force,
 	}
 
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "removed", true, opts.yes)
+	if err != nil {
+		return err
+	}
+
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
 		container = strings.Trim(container, "/")
 		if container == "" {
 			return errors.New("Container name cannot be empty")
@@ -59,7 +68,7 @@ func runRm(dockerCli *command.DockerCli, opts *rmOptions) error {
 		return dockerCli.Client().ContainerRemove(ctx, container, options)
 	})
 
-	for _, name := range opts.containers {
+	for _, name := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
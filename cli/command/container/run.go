@@ -28,6 +28,8 @@ type runOptions struct {
 	sigProxy   bool
 	name       string
 	detachKeys string
+	pull       string
+	record     string
 }
 
 // NewRunCommand create a new `docker run` command
@@ -38,9 +40,11 @@ func NewRunCommand(dockerCli *command.DockerCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Run a command in a new container",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresImageOrProfile,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			copts.Image = args[0]
+			if len(args) > 0 {
+				copts.Image = args[0]
+			}
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
@@ -56,6 +60,8 @@ func NewRunCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVar(&opts.sigProxy, "sig-proxy", true, "Proxy received signals to the process")
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
 	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.StringVar(&opts.pull, "pull", pullImageMissing, `Pull image before running ("always"|"missing"|"never")`)
+	flags.StringVar(&opts.record, "record", "", "Record the session to a file in asciicast v2 format")
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -116,6 +122,9 @@ func runContainer(dockerCli *command.DockerCli, opts *runOptions, copts *contain
 		if copts.attach.Len() != 0 {
 			return errors.New("Conflicting options: -a and -d")
 		}
+		if opts.record != "" {
+			return errors.New("Conflicting options: --record and -d")
+		}
 
 		config.AttachStdin = false
 		config.AttachStdout = false
@@ -135,9 +144,25 @@ func runContainer(dockerCli *command.DockerCli, opts *runOptions, copts *contain
 		hostConfig.ConsoleSize[0], hostConfig.ConsoleSize[1] = dockerCli.Out().GetTtySize()
 	}
 
+	// the pull policy is checked before the recording file is created, so
+	// that an invalid one does not leave an empty recording behind
+	if err := validatePullPolicy(opts.pull); err != nil {
+		return err
+	}
+
+	var recorder *sessionRecorder
+	if opts.record != "" {
+		title := "docker run " + config.Image + " " + strings.Join(config.Cmd, " ")
+		var err error
+		if recorder, err = newSessionRecorder(dockerCli, opts.record, title); err != nil {
+			return err
+		}
+		defer recorder.Close()
+	}
+
 	ctx, cancelFun := context.WithCancel(context.Background())
 
-	createResponse, err := createContainer(ctx, dockerCli, containerConfig, opts.name)
+	createResponse, err := createContainer(ctx, dockerCli, containerConfig, opts.name, opts.pull)
 	if err != nil {
 		reportError(stderr, cmdPath, err.Error(), true)
 		return runStartContainerErr(err)
@@ -164,7 +189,7 @@ func runContainer(dockerCli *command.DockerCli, opts *runOptions, copts *contain
 			dockerCli.ConfigFile().DetachKeys = opts.detachKeys
 		}
 
-		close, err := attachContainer(ctx, dockerCli, &errCh, config, createResponse.ID)
+		close, err := attachContainer(ctx, dockerCli, &errCh, config, createResponse.ID, recorder)
 		defer close()
 		if err != nil {
 			return err
@@ -192,7 +217,7 @@ func runContainer(dockerCli *command.DockerCli, opts *runOptions, copts *contain
 	}
 
 	if (config.AttachStdin || config.AttachStdout || config.AttachStderr) && config.Tty && dockerCli.Out().IsTerminal() {
-		if err := MonitorTtySize(ctx, dockerCli, createResponse.ID, false); err != nil {
+		if err := monitorTtySize(ctx, dockerCli, createResponse.ID, false, recorder); err != nil {
 			fmt.Fprintln(stderr, "Error monitoring TTY size:", err)
 		}
 	}
@@ -203,6 +228,10 @@ func runContainer(dockerCli *command.DockerCli, opts *runOptions, copts *contain
 			return err
 		}
 	}
+	if err := recorder.Close(); err != nil {
+		// the result of the session matters more than its recording
+		fmt.Fprintln(stderr, "Error writing the recording:", err)
+	}
 
 	// Detached mode: wait for the id to be displayed and return.
 	if !config.AttachStdout && !config.AttachStderr {
@@ -224,6 +253,7 @@ func attachContainer(
 	errCh *chan error,
 	config *container.Config,
 	containerID string,
+	recorder *sessionRecorder,
 ) (func(), error) {
 	stdout, stderr := dockerCli.Out(), dockerCli.Err()
 	var (
@@ -244,6 +274,8 @@ func attachContainer(
 		}
 	}
 
+	in, out, cerr = recorder.input(in), recorder.output(out), recorder.output(cerr)
+
 	options := types.ContainerAttachOptions{
 		Stream:     true,
 		Stdin:      config.AttachStdin,
@@ -0,0 +1,602 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// inspectFormatRun is the special --format of `docker container inspect`
+// printing the `docker run` command which recreates the container
+const inspectFormatRun = "run"
+
+// daemonDefaults holds the settings the daemon fills in for a container when
+// they are not given to `docker run`
+type daemonDefaults struct {
+	logDriver string
+	runtime   string
+}
+
+const defaultShmSize = 64 * 1024 * 1024
+
+// runInspectRun prints the `docker run` command of each container of refs
+func runInspectRun(ctx context.Context, dockerCli *command.DockerCli, refs []string) error {
+	client := dockerCli.Client()
+
+	defaults := daemonDefaults{logDriver: "json-file", runtime: "runc"}
+	if info, err := client.Info(ctx); err == nil {
+		defaults = daemonDefaults{logDriver: info.LoggingDriver, runtime: info.DefaultRuntime}
+	}
+
+	var errs []string
+	for _, ref := range refs {
+		c, err := client.ContainerInspect(ctx, ref)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		// The image may have been removed, in which case none of the
+		// settings can be left out as image defaults.
+		var imageConfig *container.Config
+		if image, _, err := client.ImageInspectWithRaw(ctx, c.Image); err == nil {
+			imageConfig = image.Config
+		}
+		for _, args := range runCommandLines(c, imageConfig, defaults) {
+			printCommandLine(dockerCli.Out(), args)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// printCommandLine writes args as a shell command, with one option per line.
+// The command and sub-command of args are not quoted.
+func printCommandLine(out io.Writer, args []string) {
+	// Keep the command and its sub-command on the first line.
+	first := 2
+	if len(args) < first {
+		first = len(args)
+	}
+	line := strings.Join(args[:first], " ")
+	options := true
+	for _, arg := range args[first:] {
+		// The options are all given as --flag=value, so the first other
+		// argument ends them.
+		options = options && strings.HasPrefix(arg, "-")
+		if options {
+			line += " \\\n  " + shellQuote(arg)
+		} else {
+			line += " " + shellQuote(arg)
+		}
+	}
+	fmt.Fprintln(out, line)
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+func shellQuote(arg string) string {
+	if shellSafe.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// runCommandLines returns the `docker run` command line recreating the
+// container c, followed by the `docker network connect` command lines of
+// the networks it is attached to besides the first one. Settings equal to
+// the ones of the image or to the defaults of the daemon are left out.
+func runCommandLines(c types.ContainerJSON, image *container.Config, defaults daemonDefaults) [][]string {
+	if image == nil {
+		image = &container.Config{}
+	}
+	config := c.Config
+	if config == nil {
+		config = &container.Config{}
+	}
+	hostConfig := c.HostConfig
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+	name := strings.TrimPrefix(c.Name, "/")
+
+	args := []string{"docker", "run"}
+	add := func(flag string, values ...string) {
+		for _, value := range values {
+			args = append(args, flag+"="+value)
+		}
+	}
+	addBool := func(flag string, set bool) {
+		if set {
+			args = append(args, flag)
+		}
+	}
+
+	if name != "" {
+		add("--name", name)
+	}
+	addBool("--detach", !config.AttachStdout && !config.AttachStderr)
+	addBool("--interactive", config.OpenStdin)
+	addBool("--tty", config.Tty)
+	addBool("--rm", hostConfig.AutoRemove)
+
+	if config.Hostname != "" && !strings.HasPrefix(c.ID, config.Hostname) && !hostConfig.NetworkMode.IsHost() {
+		add("--hostname", config.Hostname)
+	}
+	if config.Domainname != "" {
+		add("--domainname", config.Domainname)
+	}
+	if config.User != image.User {
+		add("--user", config.User)
+	}
+	if config.WorkingDir != image.WorkingDir {
+		add("--workdir", config.WorkingDir)
+	}
+	add("--env", missingFrom(config.Env, image.Env)...)
+	add("--label", labelsArgs(config.Labels, image.Labels)...)
+
+	// Ports
+	addBool("--publish-all", hostConfig.PublishAllPorts)
+	add("--publish", publishArgs(hostConfig.PortBindings)...)
+	var exposed []string
+	for port := range config.ExposedPorts {
+		if _, ok := image.ExposedPorts[port]; ok {
+			continue
+		}
+		if _, ok := hostConfig.PortBindings[port]; ok {
+			continue
+		}
+		exposed = append(exposed, portArg(port))
+	}
+	sort.Strings(exposed)
+	add("--expose", exposed...)
+
+	// Mounts
+	add("--volume", hostConfig.Binds...)
+	var volumes []string
+	for volume := range config.Volumes {
+		if _, ok := image.Volumes[volume]; !ok && !isMountTarget(hostConfig, volume) {
+			volumes = append(volumes, volume)
+		}
+	}
+	sort.Strings(volumes)
+	add("--volume", volumes...)
+	for _, m := range hostConfig.Mounts {
+		add("--mount", mountArg(m))
+	}
+	add("--tmpfs", mapArgs(hostConfig.Tmpfs, ":")...)
+	add("--volumes-from", hostConfig.VolumesFrom...)
+	if hostConfig.VolumeDriver != "" {
+		add("--volume-driver", hostConfig.VolumeDriver)
+	}
+
+	// Restart policy
+	switch policy := hostConfig.RestartPolicy; {
+	case policy.Name == "" || policy.Name == "no":
+	case policy.IsOnFailure() && policy.MaximumRetryCount > 0:
+		add("--restart", fmt.Sprintf("%s:%d", policy.Name, policy.MaximumRetryCount))
+	default:
+		add("--restart", policy.Name)
+	}
+	if config.StopSignal != "" && config.StopSignal != image.StopSignal && config.StopSignal != "SIGTERM" {
+		add("--stop-signal", config.StopSignal)
+	}
+	if config.StopTimeout != nil {
+		add("--stop-timeout", strconv.Itoa(*config.StopTimeout))
+	}
+	args = append(args, healthcheckArgs(config.Healthcheck, image.Healthcheck)...)
+
+	args = append(args, resourcesArgs(hostConfig.Resources)...)
+	if hostConfig.ShmSize != 0 && hostConfig.ShmSize != defaultShmSize {
+		add("--shm-size", bytesArg(hostConfig.ShmSize))
+	}
+
+	// Security options and namespaces
+	addBool("--privileged", hostConfig.Privileged)
+	addBool("--read-only", hostConfig.ReadonlyRootfs)
+	add("--cap-add", hostConfig.CapAdd...)
+	add("--cap-drop", hostConfig.CapDrop...)
+	add("--security-opt", hostConfig.SecurityOpt...)
+	add("--group-add", hostConfig.GroupAdd...)
+	add("--sysctl", mapArgs(hostConfig.Sysctls, "=")...)
+	add("--storage-opt", mapArgs(hostConfig.StorageOpt, "=")...)
+	if hostConfig.UsernsMode != "" {
+		add("--userns", string(hostConfig.UsernsMode))
+	}
+	if hostConfig.PidMode != "" {
+		add("--pid", string(hostConfig.PidMode))
+	}
+	if hostConfig.IpcMode != "" {
+		add("--ipc", string(hostConfig.IpcMode))
+	}
+	if hostConfig.UTSMode != "" {
+		add("--uts", string(hostConfig.UTSMode))
+	}
+	if hostConfig.Init != nil {
+		add("--init", strconv.FormatBool(*hostConfig.Init))
+	}
+	if hostConfig.Runtime != "" && hostConfig.Runtime != defaults.runtime {
+		add("--runtime", hostConfig.Runtime)
+	}
+	if !hostConfig.Isolation.IsDefault() {
+		add("--isolation", string(hostConfig.Isolation))
+	}
+
+	// Logging
+	if hostConfig.LogConfig.Type != "" && hostConfig.LogConfig.Type != defaults.logDriver {
+		add("--log-driver", hostConfig.LogConfig.Type)
+	}
+	add("--log-opt", mapArgs(hostConfig.LogConfig.Config, "=")...)
+
+	// Networking
+	add("--dns", hostConfig.DNS...)
+	add("--dns-search", hostConfig.DNSSearch...)
+	add("--dns-option", hostConfig.DNSOptions...)
+	add("--add-host", hostConfig.ExtraHosts...)
+	for _, link := range hostConfig.Links {
+		add("--link", linkArg(link))
+	}
+	if config.MacAddress != "" {
+		add("--mac-address", config.MacAddress)
+	}
+	networkMode := string(hostConfig.NetworkMode)
+	if networkMode != "" && networkMode != "default" && networkMode != "bridge" {
+		add("--network", networkMode)
+	}
+	var networks map[string]*network.EndpointSettings
+	if c.NetworkSettings != nil {
+		networks = c.NetworkSettings.Networks
+	}
+	if endpoint := networks[networkMode]; endpoint != nil {
+		args = append(args, endpointArgs(c.ID, endpoint)...)
+	}
+
+	// Command
+	cmd := []string(config.Cmd)
+	if !equalStrings(config.Entrypoint, image.Entrypoint) {
+		// An entrypoint given to `docker run` drops the command of the image.
+		entrypoint := []string(config.Entrypoint)
+		if len(entrypoint) == 0 {
+			entrypoint = []string{""}
+		}
+		add("--entrypoint", entrypoint[0])
+		cmd = append(entrypoint[1:], cmd...)
+	} else if equalStrings(config.Cmd, image.Cmd) {
+		cmd = nil
+	}
+	args = append(args, config.Image)
+	args = append(args, cmd...)
+
+	lines := [][]string{args}
+
+	// `docker run` attaches the container to a single network, the other
+	// ones are connected afterwards.
+	var others []string
+	for networkName := range networks {
+		if networkName != networkMode && !(networkMode == "default" && networkName == "bridge") {
+			others = append(others, networkName)
+		}
+	}
+	sort.Strings(others)
+	for _, networkName := range others {
+		connect := []string{"docker", "network", "connect"}
+		connect = append(connect, endpointArgs(c.ID, networks[networkName])...)
+		lines = append(lines, append(connect, networkName, name))
+	}
+	return lines
+}
+
+// missingFrom returns the values which are not in defaults
+func missingFrom(values, defaults []string) []string {
+	known := map[string]bool{}
+	for _, value := range defaults {
+		known[value] = true
+	}
+	var missing []string
+	for _, value := range values {
+		if !known[value] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mapArgs returns the sorted key and value pairs of m, joined by sep
+func mapArgs(m map[string]string, sep string) []string {
+	var pairs []string
+	for key, value := range m {
+		if value == "" && sep == ":" {
+			pairs = append(pairs, key)
+			continue
+		}
+		pairs = append(pairs, key+sep+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func labelsArgs(labels, defaults map[string]string) []string {
+	var pairs []string
+	for key, value := range labels {
+		if defaultValue, ok := defaults[key]; ok && defaultValue == value {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func portArg(port nat.Port) string {
+	if port.Proto() == "tcp" {
+		return port.Port()
+	}
+	return string(port)
+}
+
+func publishArgs(bindings nat.PortMap) []string {
+	var ports []string
+	for port, portBindings := range bindings {
+		for _, binding := range portBindings {
+			switch {
+			case binding.HostIP != "":
+				ports = append(ports, binding.HostIP+":"+binding.HostPort+":"+portArg(port))
+			case binding.HostPort != "":
+				ports = append(ports, binding.HostPort+":"+portArg(port))
+			default:
+				ports = append(ports, portArg(port))
+			}
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+func isMountTarget(hostConfig *container.HostConfig, target string) bool {
+	for _, bind := range hostConfig.Binds {
+		if parts := strings.Split(bind, ":"); len(parts) > 1 && parts[1] == target {
+			return true
+		}
+	}
+	for _, m := range hostConfig.Mounts {
+		if m.Target == target {
+			return true
+		}
+	}
+	_, ok := hostConfig.Tmpfs[target]
+	return ok
+}
+
+func mountArg(m mount.Mount) string {
+	fields := []string{"type=" + string(m.Type)}
+	if m.Source != "" {
+		fields = append(fields, "source="+m.Source)
+	}
+	fields = append(fields, "target="+m.Target)
+	if m.ReadOnly {
+		fields = append(fields, "readonly")
+	}
+	if m.Consistency != "" && m.Consistency != mount.ConsistencyDefault {
+		fields = append(fields, "consistency="+string(m.Consistency))
+	}
+	if options := m.BindOptions; options != nil && options.Propagation != "" {
+		fields = append(fields, "bind-propagation="+string(options.Propagation))
+	}
+	if options := m.VolumeOptions; options != nil {
+		if options.NoCopy {
+			fields = append(fields, "volume-nocopy")
+		}
+		for _, label := range mapArgs(options.Labels, "=") {
+			fields = append(fields, "volume-label="+label)
+		}
+		if options.DriverConfig != nil {
+			if options.DriverConfig.Name != "" {
+				fields = append(fields, "volume-driver="+options.DriverConfig.Name)
+			}
+			for _, option := range mapArgs(options.DriverConfig.Options, "=") {
+				fields = append(fields, "volume-opt="+option)
+			}
+		}
+	}
+	if options := m.TmpfsOptions; options != nil {
+		if options.SizeBytes != 0 {
+			fields = append(fields, "tmpfs-size="+strconv.FormatInt(options.SizeBytes, 10))
+		}
+		if options.Mode != 0 {
+			fields = append(fields, fmt.Sprintf("tmpfs-mode=%o", options.Mode))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// linkArg turns a link stored as /name:/container/alias back into name:alias
+func linkArg(link string) string {
+	parts := strings.SplitN(link, ":", 2)
+	name := strings.TrimPrefix(parts[0], "/")
+	if len(parts) == 1 {
+		return name
+	}
+	alias := parts[1][strings.LastIndex(parts[1], "/")+1:]
+	if alias == name {
+		return name
+	}
+	return name + ":" + alias
+}
+
+// endpointArgs returns the options of an attachment to a network, leaving
+// out the alias of the short container ID added by the daemon
+func endpointArgs(containerID string, endpoint *network.EndpointSettings) []string {
+	var args []string
+	if ipam := endpoint.IPAMConfig; ipam != nil {
+		if ipam.IPv4Address != "" {
+			args = append(args, "--ip="+ipam.IPv4Address)
+		}
+		if ipam.IPv6Address != "" {
+			args = append(args, "--ip6="+ipam.IPv6Address)
+		}
+		for _, ip := range ipam.LinkLocalIPs {
+			args = append(args, "--link-local-ip="+ip)
+		}
+	}
+	for _, alias := range endpoint.Aliases {
+		if alias != "" && !strings.HasPrefix(containerID, alias) {
+			args = append(args, "--alias="+alias)
+		}
+	}
+	return args
+}
+
+func healthcheckArgs(health, imageHealth *container.HealthConfig) []string {
+	if health == nil || (imageHealth != nil && equalHealthchecks(*health, *imageHealth)) {
+		return nil
+	}
+	if len(health.Test) > 0 && health.Test[0] == "NONE" {
+		return []string{"--no-healthcheck"}
+	}
+
+	var args []string
+	if len(health.Test) > 1 && (imageHealth == nil || !equalStrings(health.Test, imageHealth.Test)) {
+		test := strings.Join(health.Test[1:], " ")
+		args = append(args, "--health-cmd="+test)
+	}
+	if health.Interval != 0 {
+		args = append(args, "--health-interval="+health.Interval.String())
+	}
+	if health.Timeout != 0 {
+		args = append(args, "--health-timeout="+health.Timeout.String())
+	}
+	if health.StartPeriod != 0 {
+		args = append(args, "--health-start-period="+health.StartPeriod.String())
+	}
+	if health.Retries != 0 {
+		args = append(args, "--health-retries="+strconv.Itoa(health.Retries))
+	}
+	return args
+}
+
+func equalHealthchecks(a, b container.HealthConfig) bool {
+	return equalStrings(a.Test, b.Test) && a.Interval == b.Interval && a.Timeout == b.Timeout &&
+		a.StartPeriod == b.StartPeriod && a.Retries == b.Retries
+}
+
+func resourcesArgs(resources container.Resources) []string {
+	var args []string
+	addBytes := func(flag string, value int64) {
+		if value > 0 {
+			args = append(args, flag+"="+bytesArg(value))
+		}
+	}
+	addInt := func(flag string, value int64) {
+		if value != 0 {
+			args = append(args, flag+"="+strconv.FormatInt(value, 10))
+		}
+	}
+	addString := func(flag, value string) {
+		if value != "" {
+			args = append(args, flag+"="+value)
+		}
+	}
+
+	addBytes("--memory", resources.Memory)
+	addBytes("--memory-reservation", resources.MemoryReservation)
+	// The daemon allows as much swap as memory when the swap is not set.
+	switch {
+	case resources.MemorySwap == -1:
+		args = append(args, "--memory-swap=-1")
+	case resources.MemorySwap > 0 && resources.MemorySwap != 2*resources.Memory:
+		addBytes("--memory-swap", resources.MemorySwap)
+	}
+	if swappiness := resources.MemorySwappiness; swappiness != nil && *swappiness != -1 {
+		addInt("--memory-swappiness", *swappiness)
+	}
+	addBytes("--kernel-memory", resources.KernelMemory)
+	if resources.OomKillDisable != nil && *resources.OomKillDisable {
+		args = append(args, "--oom-kill-disable")
+	}
+	if resources.NanoCPUs != 0 {
+		args = append(args, "--cpus="+strconv.FormatFloat(float64(resources.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	addInt("--cpu-shares", resources.CPUShares)
+	addInt("--cpu-period", resources.CPUPeriod)
+	addInt("--cpu-quota", resources.CPUQuota)
+	addInt("--cpu-rt-period", resources.CPURealtimePeriod)
+	addInt("--cpu-rt-runtime", resources.CPURealtimeRuntime)
+	addString("--cpuset-cpus", resources.CpusetCpus)
+	addString("--cpuset-mems", resources.CpusetMems)
+	addInt("--blkio-weight", int64(resources.BlkioWeight))
+	for _, device := range resources.BlkioWeightDevice {
+		args = append(args, "--blkio-weight-device="+device.String())
+	}
+	for _, device := range resources.BlkioDeviceReadBps {
+		args = append(args, "--device-read-bps="+device.Path+":"+strconv.FormatUint(device.Rate, 10))
+	}
+	for _, device := range resources.BlkioDeviceWriteBps {
+		args = append(args, "--device-write-bps="+device.Path+":"+strconv.FormatUint(device.Rate, 10))
+	}
+	for _, device := range resources.BlkioDeviceReadIOps {
+		args = append(args, "--device-read-iops="+device.Path+":"+strconv.FormatUint(device.Rate, 10))
+	}
+	for _, device := range resources.BlkioDeviceWriteIOps {
+		args = append(args, "--device-write-iops="+device.Path+":"+strconv.FormatUint(device.Rate, 10))
+	}
+	addInt("--pids-limit", resources.PidsLimit)
+	for _, ulimit := range resources.Ulimits {
+		args = append(args, "--ulimit="+ulimit.String())
+	}
+	for _, device := range resources.Devices {
+		arg := device.PathOnHost
+		if device.PathInContainer != "" && device.PathInContainer != device.PathOnHost {
+			arg += ":" + device.PathInContainer
+		}
+		if device.CgroupPermissions != "" && device.CgroupPermissions != "rwm" {
+			if device.PathInContainer == device.PathOnHost {
+				arg += ":" + device.PathInContainer
+			}
+			arg += ":" + device.CgroupPermissions
+		}
+		args = append(args, "--device="+arg)
+	}
+	for _, rule := range resources.DeviceCgroupRules {
+		args = append(args, "--device-cgroup-rule="+rule)
+	}
+	addString("--cgroup-parent", resources.CgroupParent)
+	return args
+}
+
+// bytesArg formats a size in the largest unit dividing it exactly
+func bytesArg(size int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if size%unit.size == 0 {
+			return strconv.FormatInt(size/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(size, 10)
+}
@@ -0,0 +1,149 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandLinesLeavesOutDefaults(t *testing.T) {
+	image := &container.Config{
+		Env:          []string{"PATH=/usr/bin"},
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		WorkingDir:   "/srv",
+		ExposedPorts: nat.PortSet{"80/tcp": {}},
+		Labels:       map[string]string{"maintainer": "someone"},
+	}
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   "0123456789abcdef",
+			Name: "/web",
+			HostConfig: &container.HostConfig{
+				NetworkMode: "default",
+				LogConfig:   container.LogConfig{Type: "json-file"},
+				Runtime:     "runc",
+				ShmSize:     defaultShmSize,
+			},
+		},
+		Config: &container.Config{
+			Hostname:     "0123456789ab",
+			Image:        "nginx",
+			Env:          image.Env,
+			Cmd:          image.Cmd,
+			WorkingDir:   image.WorkingDir,
+			ExposedPorts: image.ExposedPorts,
+			Labels:       image.Labels,
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{"bridge": {}},
+		},
+	}
+
+	lines := runCommandLines(c, image, daemonDefaults{logDriver: "json-file", runtime: "runc"})
+	assert.Equal(t, [][]string{{"docker", "run", "--name=web", "--detach", "nginx"}}, lines)
+}
+
+func TestRunCommandLines(t *testing.T) {
+	retries := 3
+	oomKillDisable := true
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   "0123456789abcdef",
+			Name: "/app",
+			HostConfig: &container.HostConfig{
+				Binds:         []string{"/data:/data:ro"},
+				Mounts:        []mount.Mount{{Type: mount.TypeVolume, Source: "cache", Target: "/cache", ReadOnly: true}},
+				NetworkMode:   "backend",
+				PortBindings:  nat.PortMap{"8080/tcp": {{HostIP: "127.0.0.1", HostPort: "80"}}, "53/udp": {{}}},
+				RestartPolicy: container.RestartPolicy{Name: "on-failure", MaximumRetryCount: retries},
+				CapAdd:        []string{"NET_ADMIN"},
+				SecurityOpt:   []string{"no-new-privileges"},
+				LogConfig:     container.LogConfig{Type: "syslog", Config: map[string]string{"tag": "app"}},
+				Resources: container.Resources{
+					Memory:         512 * 1024 * 1024,
+					MemorySwap:     1024 * 1024 * 1024,
+					NanoCPUs:       1500000000,
+					OomKillDisable: &oomKillDisable,
+					Ulimits:        []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+				},
+			},
+		},
+		Config: &container.Config{
+			Hostname:   "app.local",
+			Image:      "example/app:1.0",
+			Env:        []string{"PATH=/usr/bin", "MODE=production"},
+			Labels:     map[string]string{"team": "web"},
+			Tty:        true,
+			OpenStdin:  true,
+			Entrypoint: []string{"/entrypoint.sh", "--verbose"},
+			Cmd:        []string{"serve"},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"backend": {
+					IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "10.0.0.5"},
+					Aliases:    []string{"0123456789ab", "api"},
+				},
+				"frontend": {Aliases: []string{"app"}},
+			},
+		},
+	}
+
+	lines := runCommandLines(c, &container.Config{Env: []string{"PATH=/usr/bin"}}, daemonDefaults{logDriver: "json-file"})
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{
+		"docker", "run",
+		"--name=app",
+		"--detach",
+		"--interactive",
+		"--tty",
+		"--hostname=app.local",
+		"--env=MODE=production",
+		"--label=team=web",
+		"--publish=127.0.0.1:80:8080",
+		"--publish=53/udp",
+		"--volume=/data:/data:ro",
+		"--mount=type=volume,source=cache,target=/cache,readonly",
+		"--restart=on-failure:3",
+		"--memory=512m",
+		"--oom-kill-disable",
+		"--cpus=1.5",
+		"--ulimit=nofile=1024:2048",
+		"--cap-add=NET_ADMIN",
+		"--security-opt=no-new-privileges",
+		"--log-driver=syslog",
+		"--log-opt=tag=app",
+		"--network=backend",
+		"--ip=10.0.0.5",
+		"--alias=api",
+		"--entrypoint=/entrypoint.sh",
+		"example/app:1.0",
+		"--verbose", "serve",
+	}, lines[0])
+	assert.Equal(t, []string{"docker", "network", "connect", "--alias=app", "frontend", "app"}, lines[1])
+}
+
+func TestLinkArg(t *testing.T) {
+	assert.Equal(t, "db:database", linkArg("/db:/app/database"))
+	assert.Equal(t, "db", linkArg("/db:/app/db"))
+}
+
+func TestBytesArg(t *testing.T) {
+	assert.Equal(t, "2g", bytesArg(2*1024*1024*1024))
+	assert.Equal(t, "1536m", bytesArg(1536*1024*1024))
+	assert.Equal(t, "1000", bytesArg(1000))
+}
+
+func TestPrintCommandLine(t *testing.T) {
+	out := new(bytes.Buffer)
+	printCommandLine(out, []string{"docker", "run", "--env=GREETING=hello world", "--label=quote='", "busybox", "echo", "hi"})
+	assert.Equal(t, "docker run \\\n  '--env=GREETING=hello world' \\\n  '--label=quote='\\''' busybox echo hi\n", out.String())
+}
@@ -5,8 +5,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -15,18 +15,20 @@ import (
 type stopOptions struct {
 	time        int
 	timeChanged bool
+	filter      opts.FilterOpt
+	force       bool
 
 	containers []string
 }
 
 // NewStopCommand creates a new cobra.Command for `docker stop`
 func NewStopCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts stopOptions
+	opts := stopOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "stop [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Stop one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			opts.timeChanged = cmd.Flags().Changed("time")
@@ -36,6 +38,8 @@ func NewStopCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.time, "time", "t", 10, "Seconds to wait for stop before killing it")
+	flags.Var(&opts.filter, "filter", "Filter the containers to stop, as with 'docker ps --filter'")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
 	return cmd
 }
 
@@ -48,12 +52,17 @@ func runStop(dockerCli *command.DockerCli, opts *stopOptions) error {
 		timeout = &timeoutValue
 	}
 
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "stopped", false, opts.force)
+	if err != nil {
+		return err
+	}
+
 	var errs []string
 
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, id string) error {
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, id string) error {
 		return dockerCli.Client().ContainerStop(ctx, id, timeout)
 	})
-	for _, container := range opts.containers {
+	for _, container := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
@@ -0,0 +1,410 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type syncOptions struct {
+	source      string
+	destination string
+	delete      bool
+	watch       bool
+	interval    time.Duration
+	excludes    opts.ListOpts
+}
+
+// NewSyncCommand creates a new `docker container sync` command
+func NewSyncCommand(dockerCli *command.DockerCli) *cobra.Command {
+	options := syncOptions{excludes: opts.NewListOpts(nil)}
+
+	cmd := &cobra.Command{
+		Use:   "sync [OPTIONS] LOCAL_PATH CONTAINER:DEST_PATH",
+		Short: "Copy the new and changed files of a local directory into a container",
+		Long: strings.Join([]string{
+			"Copy the new and changed files of a local directory into a container\n",
+			"\nFiles are compared by type, size and modification time with the\n",
+			"files of the destination directory, and only those that differ are\n",
+			"sent. Deleting files requires the rm command in the container.",
+		}, ""),
+		Args: cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.source = args[0]
+			options.destination = args[1]
+			return runSync(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.delete, "delete", false, "Delete files of the destination which do not exist locally")
+	flags.BoolVarP(&options.watch, "watch", "w", false, "Keep watching the local directory and sync its changes")
+	flags.DurationVar(&options.interval, "interval", time.Second, "Interval between checks for local changes in watch mode")
+	flags.Var(&options.excludes, "exclude", "Exclude files matching a pattern, in .dockerignore syntax")
+
+	return cmd
+}
+
+// syncEntry describes a file of a synced directory
+type syncEntry struct {
+	mode     os.FileMode
+	size     int64
+	modTime  int64
+	linkname string
+}
+
+// differs returns whether entry has to be sent to replace other
+func (entry syncEntry) differs(other syncEntry) bool {
+	if entry.mode&os.ModeType != other.mode&os.ModeType {
+		return true
+	}
+	switch {
+	case entry.mode.IsDir():
+		return false
+	case entry.mode&os.ModeSymlink != 0:
+		return entry.linkname != other.linkname
+	default:
+		return entry.size != other.size || entry.modTime != other.modTime
+	}
+}
+
+// syncTree maps the slash separated paths of the files of a directory,
+// relative to the directory, to their description
+type syncTree map[string]syncEntry
+
+// syncTarget is the destination directory of a sync
+type syncTarget struct {
+	container string
+	// dir is the directory the archive is extracted to, and base the name
+	// of the destination directory in it
+	dir  string
+	base string
+}
+
+func (t syncTarget) path(rel string) string {
+	return path.Join(t.dir, t.base, rel)
+}
+
+func runSync(dockerCli *command.DockerCli, options syncOptions) error {
+	container, dstPath := splitCpArg(options.destination)
+	if container == "" {
+		return errors.New("destination must be a container path, in the form CONTAINER:DEST_PATH")
+	}
+	srcContainer, _ := splitCpArg(options.source)
+	if srcContainer != "" {
+		return errors.New("source must be a local directory")
+	}
+	srcPath, err := filepath.Abs(options.source)
+	if err != nil {
+		return err
+	}
+	if srcStat, err := os.Stat(srcPath); err != nil {
+		return err
+	} else if !srcStat.IsDir() {
+		return errors.Errorf("source %q must be a directory", options.source)
+	}
+
+	dir, base := path.Split(path.Clean(dstPath))
+	if base == "" || base == "/" || base == "." {
+		return errors.Errorf("invalid destination directory %q", dstPath)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	target := syncTarget{container: container, dir: dir, base: base}
+
+	var excludes *fileutils.PatternMatcher
+	if patterns := options.excludes.GetAll(); len(patterns) > 0 {
+		if excludes, err = fileutils.NewPatternMatcher(patterns); err != nil {
+			return errors.Wrap(err, "invalid exclude pattern")
+		}
+	}
+
+	ctx := context.Background()
+
+	remote, err := remoteSyncTree(ctx, dockerCli, target, excludes)
+	if err != nil {
+		return err
+	}
+	for {
+		local, err := localSyncTree(srcPath, excludes)
+		if err != nil {
+			return err
+		}
+		if err := syncChanges(ctx, dockerCli, srcPath, target, local, remote, options.delete); err != nil {
+			return err
+		}
+		if !options.watch {
+			return nil
+		}
+		remote = local
+		time.Sleep(options.interval)
+	}
+}
+
+// syncChanges sends the entries of local which differ from remote, and
+// deletes the entries of remote which are gone locally if deleteRemoved is
+// set.
+func syncChanges(ctx context.Context, dockerCli *command.DockerCli, srcPath string, target syncTarget, local, remote syncTree, deleteRemoved bool) error {
+	changed, removed := diffSyncTrees(local, remote)
+	if !deleteRemoved {
+		removed = nil
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	if len(changed) > 0 {
+		content, w := io.Pipe()
+		go func() {
+			w.CloseWithError(writeSyncArchive(w, srcPath, target.base, changed))
+		}()
+		err := dockerCli.Client().CopyToContainer(ctx, target.container, target.dir, content, types.CopyToContainerOptions{
+			AllowOverwriteDirWithFile: true,
+		})
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		if err := removeContainerPaths(ctx, dockerCli, target, removed); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s: %d updated, %d deleted\n", time.Now().Format("15:04:05"), len(changed), len(removed))
+	return nil
+}
+
+// diffSyncTrees returns the sorted paths of the entries of local which are
+// missing from remote or differ from it, and of the entries of remote which
+// are missing from local. The entries below a missing directory of remote
+// are not listed.
+func diffSyncTrees(local, remote syncTree) (changed, removed []string) {
+	for rel, entry := range local {
+		if remoteEntry, ok := remote[rel]; !ok || entry.differs(remoteEntry) {
+			changed = append(changed, rel)
+		}
+	}
+	for rel := range remote {
+		if _, ok := local[rel]; !ok {
+			removed = append(removed, rel)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+
+	// Leave out the entries of removed directories.
+	topLevel := removed[:0]
+	for _, rel := range removed {
+		if !hasRemovedParent(remote, local, rel) {
+			topLevel = append(topLevel, rel)
+		}
+	}
+	return changed, topLevel
+}
+
+func hasRemovedParent(remote, local syncTree, rel string) bool {
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		if _, ok := local[dir]; !ok {
+			if _, ok := remote[dir]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isExcluded(excludes *fileutils.PatternMatcher, rel string) (bool, error) {
+	if excludes == nil {
+		return false, nil
+	}
+	return excludes.Matches(filepath.FromSlash(rel))
+}
+
+// localSyncTree lists the files of the local directory root
+func localSyncTree(root string, excludes *fileutils.PatternMatcher) (syncTree, error) {
+	tree := syncTree{}
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		excluded, err := isExcluded(excludes, rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			// Entries of an excluded directory may be included again by an
+			// exclusion pattern.
+			if info.IsDir() && !excludes.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		entry := syncEntry{mode: info.Mode(), size: info.Size(), modTime: info.ModTime().Unix()}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if entry.linkname, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+		tree[rel] = entry
+		return nil
+	})
+	return tree, err
+}
+
+// remoteSyncTree lists the files of the destination directory from its
+// archive. The tree is empty if the directory does not exist yet.
+func remoteSyncTree(ctx context.Context, dockerCli *command.DockerCli, target syncTarget, excludes *fileutils.PatternMatcher) (syncTree, error) {
+	tree := syncTree{}
+	client := dockerCli.Client()
+
+	dstPath := target.path("")
+	stat, err := client.ContainerStatPath(ctx, target.container, dstPath)
+	if err != nil {
+		// Make sure the container exists, in which case the destination
+		// does not.
+		if _, inspectErr := client.ContainerInspect(ctx, target.container); inspectErr != nil {
+			return nil, inspectErr
+		}
+		return tree, nil
+	}
+	if !stat.Mode.IsDir() {
+		return nil, errors.Errorf("destination \"%s:%s\" must be a directory", target.container, dstPath)
+	}
+
+	content, _, err := client.CopyFromContainer(ctx, target.container, dstPath)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tree, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(hdr.Name, "/")
+		i := strings.Index(name, "/")
+		if i < 0 {
+			// The destination directory itself
+			continue
+		}
+		rel := name[i+1:]
+		excluded, err := isExcluded(excludes, rel)
+		if err != nil {
+			return nil, err
+		}
+		if excluded {
+			continue
+		}
+		tree[rel] = syncEntry{
+			mode:     hdr.FileInfo().Mode(),
+			size:     hdr.Size,
+			modTime:  hdr.ModTime.Unix(),
+			linkname: hdr.Linkname,
+		}
+	}
+}
+
+// writeSyncArchive writes a tar archive of the paths of the local directory
+// root, named after their path in the base directory.
+func writeSyncArchive(w io.Writer, root, base string, paths []string) error {
+	tw := tar.NewWriter(w)
+	for _, rel := range paths {
+		filePath := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Lstat(filePath)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(base, rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			if err := copyFileContent(tw, filePath); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Close()
+}
+
+func copyFileContent(w io.Writer, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// removeContainerPaths deletes paths of the destination directory by running
+// rm in the container
+func removeContainerPaths(ctx context.Context, dockerCli *command.DockerCli, target syncTarget, paths []string) error {
+	cmd := []string{"rm", "-rf", "--"}
+	for _, rel := range paths {
+		cmd = append(cmd, target.path(rel))
+	}
+	execConfig := types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	stderr := new(bytes.Buffer)
+	status, err := execInContainer(ctx, dockerCli, target.container, execConfig, ioutil.Discard, stderr)
+	if err != nil {
+		return err
+	}
+	if status != 0 {
+		return errors.Errorf("failed to delete files in container %s: %s", target.container, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
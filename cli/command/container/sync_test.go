@@ -0,0 +1,108 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSyncTrees(t *testing.T) {
+	file := syncEntry{mode: 0644, size: 10, modTime: 100}
+	dir := syncEntry{mode: os.ModeDir | 0755}
+
+	local := syncTree{
+		"same":         file,
+		"newer":        syncEntry{mode: 0644, size: 10, modTime: 200},
+		"resized":      syncEntry{mode: 0644, size: 20, modTime: 100},
+		"new":          file,
+		"dir":          dir,
+		"dir/same":     file,
+		"link":         syncEntry{mode: os.ModeSymlink | 0777, linkname: "same"},
+		"was-dir":      file,
+		"dir-modified": syncEntry{mode: os.ModeDir | 0700, modTime: 300},
+	}
+	remote := syncTree{
+		"same":         file,
+		"newer":        file,
+		"resized":      file,
+		"dir":          dir,
+		"dir/same":     file,
+		"dir/gone":     file,
+		"link":         syncEntry{mode: os.ModeSymlink | 0777, linkname: "newer"},
+		"was-dir":      dir,
+		"was-dir/a":    file,
+		"gone":         dir,
+		"gone/a":       file,
+		"gone/b/c":     file,
+		"gone-too":     file,
+		"dir-modified": dir,
+	}
+
+	changed, removed := diffSyncTrees(local, remote)
+	assert.Equal(t, []string{"link", "new", "newer", "resized", "was-dir"}, changed)
+	assert.Equal(t, []string{"dir/gone", "gone", "gone-too", "was-dir/a"}, removed)
+}
+
+func TestLocalSyncTree(t *testing.T) {
+	root, err := ioutil.TempDir("", "sync-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "vendor"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules", "pkg"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "debug.log"), []byte("log"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "node_modules", "pkg", "index.js"), []byte(""), 0644))
+
+	excludes, err := fileutils.NewPatternMatcher([]string{"*.log", "node_modules"})
+	require.NoError(t, err)
+
+	tree, err := localSyncTree(root, excludes)
+	require.NoError(t, err)
+
+	var paths []string
+	for rel := range tree {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	assert.Equal(t, []string{"src", "src/main.go", "src/vendor"}, paths)
+	assert.Equal(t, int64(len("package main")), tree["src/main.go"].size)
+	assert.True(t, tree["src"].mode.IsDir())
+}
+
+func TestWriteSyncArchive(t *testing.T) {
+	root, err := ioutil.TempDir("", "sync-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0644))
+	modTime := time.Unix(1500000000, 0)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "src", "main.go"), modTime, modTime))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeSyncArchive(buf, root, "app", []string{"src", "src/main.go"}))
+
+	tr := tar.NewReader(buf)
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "app/src/", hdr.Name)
+	assert.Equal(t, byte(tar.TypeDir), hdr.Typeflag)
+
+	hdr, err = tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "app/src/main.go", hdr.Name)
+	assert.Equal(t, modTime.Unix(), hdr.ModTime.Unix())
+	content, err := ioutil.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(content))
+}
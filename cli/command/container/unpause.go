@@ -4,30 +4,37 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
 type unpauseOptions struct {
+	filter opts.FilterOpt
+	force  bool
+
 	containers []string
 }
 
 // NewUnpauseCommand creates a new cobra.Command for `docker unpause`
 func NewUnpauseCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts unpauseOptions
+	opts := unpauseOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
-		Use:   "unpause CONTAINER [CONTAINER...]",
+		Use:   "unpause [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Unpause all processes within one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runUnpause(dockerCli, &opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.Var(&opts.filter, "filter", "Filter the containers to unpause, as with 'docker ps --filter'")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
 	return cmd
 }
 
@@ -35,8 +42,13 @@ func runUnpause(dockerCli *command.DockerCli, opts *unpauseOptions) error {
 	ctx := context.Background()
 
 	var errs []string
-	errChan := parallelOperation(ctx, opts.containers, dockerCli.Client().ContainerUnpause)
-	for _, container := range opts.containers {
+	containers, err := filterContainers(ctx, dockerCli, opts.containers, opts.filter, "unpaused", false, opts.force)
+	if err != nil {
+		return err
+	}
+
+	errChan := parallelOperation(ctx, containers, dockerCli.Client().ContainerUnpause)
+	for _, container := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
@@ -3,8 +3,8 @@ package container
 import (
 	"fmt"
 	"strings"
+	"sync"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/opts"
 	containertypes "github.com/docker/docker/api/types/container"
@@ -29,6 +29,8 @@ type updateOptions struct {
 	kernelMemory       opts.MemBytes
 	restartPolicy      string
 	cpus               opts.NanoCPUs
+	filter             opts.FilterOpt
+	force              bool
 
 	nFlag int
 
@@ -37,15 +39,21 @@ type updateOptions struct {
 
 // NewUpdateCommand creates a new cobra.Command for `docker update`
 func NewUpdateCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var options updateOptions
+	options := updateOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "update [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Update configuration of one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  requiresContainersOrFilter,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.containers = args
 			options.nFlag = cmd.Flags().NFlag()
+			// the flags selecting the containers do not update anything
+			for _, name := range []string{"filter", "force"} {
+				if cmd.Flags().Changed(name) {
+					options.nFlag--
+				}
+			}
 			return runUpdate(dockerCli, &options)
 		},
 	}
@@ -69,6 +77,8 @@ func NewUpdateCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags.Var(&options.cpus, "cpus", "Number of CPUs")
 	flags.SetAnnotation("cpus", "version", []string{"1.29"})
+	flags.Var(&options.filter, "filter", "Filter the containers to update, as with 'docker ps --filter'")
+	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation of the containers matching the filter")
 
 	return cmd
 }
@@ -115,14 +125,25 @@ func runUpdate(dockerCli *command.DockerCli, options *updateOptions) error {
 		warns []string
 		errs  []string
 	)
-	for _, container := range options.containers {
+	containers, err := filterContainers(ctx, dockerCli, options.containers, options.filter, "updated", true, options.force)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
 		r, err := dockerCli.Client().ContainerUpdate(ctx, container, updateConfig)
-		if err != nil {
+		mu.Lock()
+		warns = append(warns, r.Warnings...)
+		mu.Unlock()
+		return err
+	})
+	for _, container := range containers {
+		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 		} else {
 			fmt.Fprintln(dockerCli.Out(), container)
 		}
-		warns = append(warns, r.Warnings...)
 	}
 	if len(warns) > 0 {
 		fmt.Fprintln(dockerCli.Out(), strings.Join(warns, "\n"))
@@ -1,15 +1,21 @@
 package container
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/versions"
 	clientapi "github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
@@ -140,3 +146,62 @@ func parallelOperation(ctx context.Context, containers []string, op func(ctx con
 	}()
 	return errChan
 }
+
+// requiresContainersOrFilter checks that at least one container is given as
+// an argument, unless containers are selected with --filter.
+func requiresContainersOrFilter(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("filter") {
+		return nil
+	}
+	return cli.RequiresMinArgs(1)(cmd, args)
+}
+
+// filterContainers returns the containers, followed by the containers
+// matching filter. Like "docker ps", the filter only matches running
+// containers unless all is set. Unless force is set, the containers matching
+// filter are shown and the user is asked to confirm the action on them, and
+// an error is returned if the user declines, so that no container is
+// affected.
+func filterContainers(ctx context.Context, dockerCli command.Cli, containers []string, filter opts.FilterOpt, action string, all, force bool) ([]string, error) {
+	if filter.Value().Len() == 0 {
+		return containers, nil
+	}
+
+	list, err := dockerCli.Client().ContainerList(ctx, types.ContainerListOptions{
+		All:     all,
+		Filters: filter.Value(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, container := range containers {
+		seen[container] = true
+	}
+	var matched []string
+	for _, c := range list {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		if !seen[name] && !seen[c.ID] {
+			seen[name] = true
+			matched = append(matched, name)
+		}
+	}
+	if len(matched) == 0 && len(containers) == 0 {
+		return nil, errors.New("no containers match the filter")
+	}
+
+	if !force && len(matched) > 0 {
+		fmt.Fprintf(dockerCli.Out(), "The following containers match the filter and will be %s:\n", action)
+		for _, name := range matched {
+			fmt.Fprintf(dockerCli.Out(), "  %s\n", name)
+		}
+		if !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), "") {
+			return nil, errors.New("operation cancelled")
+		}
+	}
+	return append(containers, matched...), nil
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func newFilterTestCli(out *bytes.Buffer, answer string) *test.FakeCli {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options types.ContainerListOptions) ([]types.Container, error) {
+			if options.Filters.Get("label")[0] != "app=web" {
+				return nil, nil
+			}
+			// web2 is stopped
+			if !options.All {
+				return []types.Container{{ID: "id1", Names: []string{"/web1"}}}, nil
+			}
+			return []types.Container{
+				{ID: "id1", Names: []string{"/web1"}},
+				{ID: "id2", Names: []string{"/web2"}},
+			}, nil
+		},
+	}, out)
+	cli.SetIn(command.NewInStream(ioutil.NopCloser(strings.NewReader(answer))))
+	return cli
+}
+
+func newLabelFilter(t *testing.T, value string) opts.FilterOpt {
+	filter := opts.NewFilterOpt()
+	require.NoError(t, filter.Set("label="+value))
+	return filter
+}
+
+func TestFilterContainersWithoutFilter(t *testing.T) {
+	cli := newFilterTestCli(new(bytes.Buffer), "")
+	containers, err := filterContainers(context.Background(), cli, []string{"db"}, opts.NewFilterOpt(), "stopped", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db"}, containers)
+}
+
+func TestFilterContainersConfirmed(t *testing.T) {
+	out := new(bytes.Buffer)
+	cli := newFilterTestCli(out, "y\n")
+	containers, err := filterContainers(context.Background(), cli, []string{"db", "web2"}, newLabelFilter(t, "app=web"), "stopped", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "web2", "web1"}, containers)
+	assert.Contains(t, out.String(), "The following containers match the filter and will be stopped:\n  web1\n")
+}
+
+func TestFilterContainersDeclined(t *testing.T) {
+	cli := newFilterTestCli(new(bytes.Buffer), "n\n")
+	_, err := filterContainers(context.Background(), cli, []string{"db"}, newLabelFilter(t, "app=web"), "stopped", false, false)
+	assert.EqualError(t, err, "operation cancelled")
+}
+
+func TestFilterContainersForce(t *testing.T) {
+	out := new(bytes.Buffer)
+	cli := newFilterTestCli(out, "")
+	containers, err := filterContainers(context.Background(), cli, nil, newLabelFilter(t, "app=web"), "removed", true, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web1", "web2"}, containers)
+	assert.Empty(t, out.String())
+}
+
+func TestFilterContainersRunningOnly(t *testing.T) {
+	cli := newFilterTestCli(new(bytes.Buffer), "")
+	containers, err := filterContainers(context.Background(), cli, nil, newLabelFilter(t, "app=web"), "stopped", false, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web1"}, containers)
+}
+
+func TestFilterContainersNoMatch(t *testing.T) {
+	cli := newFilterTestCli(new(bytes.Buffer), "")
+	_, err := filterContainers(context.Background(), cli, nil, newLabelFilter(t, "app=db"), "removed", true, true)
+	assert.EqualError(t, err, "no containers match the filter")
+}
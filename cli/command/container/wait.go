@@ -3,16 +3,27 @@ package container
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
+const (
+	waitConditionExited  = "exited"
+	waitConditionRunning = "running"
+	waitConditionHealthy = "healthy"
+)
+
 type waitOptions struct {
 	containers []string
+	condition  string
+	timeout    time.Duration
 }
 
 // NewWaitCommand creates a new cobra.Command for `docker wait`
@@ -20,31 +31,130 @@ func NewWaitCommand(dockerCli *command.DockerCli) *cobra.Command {
 	var opts waitOptions
 
 	cmd := &cobra.Command{
-		Use:   "wait CONTAINER [CONTAINER...]",
+		Use:   "wait [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Block until one or more containers stop, then print their exit codes",
-		Args:  cli.RequiresMinArgs(1),
+		Long: strings.Join([]string{
+			"Block until one or more containers stop, then print their exit codes\n",
+			"\nWith --condition=running or --condition=healthy, block until the\n",
+			"containers are running or healthy instead, then print their names.",
+		}, ""),
+		Args: cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runWait(dockerCli, &opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.condition, "condition", waitConditionExited, "Condition to wait for (exited, running, healthy)")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Maximum time to wait for all the containers (0 to wait forever)")
+
 	return cmd
 }
 
 func runWait(dockerCli *command.DockerCli, opts *waitOptions) error {
+	switch opts.condition {
+	case waitConditionExited, waitConditionRunning, waitConditionHealthy:
+	default:
+		return errors.Errorf("invalid condition %q: must be one of %s, %s or %s",
+			opts.condition, waitConditionExited, waitConditionRunning, waitConditionHealthy)
+	}
+
 	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
 
 	var errs []string
 	for _, container := range opts.containers {
-		status, err := dockerCli.Client().ContainerWait(ctx, container)
-		if err != nil {
-			errs = append(errs, err.Error())
+		if opts.condition == waitConditionExited {
+			status, err := dockerCli.Client().ContainerWait(ctx, container)
+			if err != nil {
+				errs = append(errs, waitError(ctx, container, opts.condition, err).Error())
+				continue
+			}
+			fmt.Fprintf(dockerCli.Out(), "%d\n", status)
 			continue
 		}
-		fmt.Fprintf(dockerCli.Out(), "%d\n", status)
+
+		if err := waitForCondition(ctx, dockerCli, container, opts.condition); err != nil {
+			errs = append(errs, waitError(ctx, container, opts.condition, err).Error())
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "%s\n", container)
 	}
 	if len(errs) > 0 {
 		return errors.New(strings.Join(errs, "\n"))
 	}
 	return nil
 }
+
+// waitError replaces the error caused by the timeout with a clearer one
+func waitError(ctx context.Context, container, condition string, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.Errorf("timeout waiting for container %s to be %s", container, condition)
+	}
+	return err
+}
+
+// waitForCondition blocks until the container is running or healthy. The
+// container is inspected again on each of its events, so no change of state
+// is missed between the inspect and the subscription to the events.
+func waitForCondition(ctx context.Context, dockerCli *command.DockerCli, container, condition string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f := filters.NewArgs()
+	f.Add("type", "container")
+	f.Add("container", container)
+	eventq, errq := dockerCli.Client().Events(ctx, types.EventsOptions{Filters: f})
+
+	for {
+		c, err := dockerCli.Client().ContainerInspect(ctx, container)
+		if err != nil {
+			return err
+		}
+		if done, err := checkWaitCondition(c, condition); err != nil || done {
+			return err
+		}
+
+		select {
+		case <-eventq:
+		case err := <-errq:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkWaitCondition returns whether the container meets the condition, or
+// an error if it can no longer meet it.
+func checkWaitCondition(c types.ContainerJSON, condition string) (bool, error) {
+	name := strings.TrimPrefix(c.Name, "/")
+	state := c.State
+	if state == nil {
+		return false, nil
+	}
+	switch condition {
+	case waitConditionRunning:
+		return state.Running, nil
+	case waitConditionHealthy:
+		if c.Config == nil || c.Config.Healthcheck == nil ||
+			len(c.Config.Healthcheck.Test) == 0 || c.Config.Healthcheck.Test[0] == "NONE" {
+			return false, errors.Errorf("container %s has no healthcheck", name)
+		}
+		if !state.Running {
+			if state.StartedAt != "" && state.StartedAt != "0001-01-01T00:00:00Z" {
+				return false, errors.Errorf("container %s exited with code %d before becoming healthy", name, state.ExitCode)
+			}
+			// The container is created but not started yet.
+			return false, nil
+		}
+		return state.Health != nil && state.Health.Status == types.Healthy, nil
+	default:
+		return false, errors.Errorf("invalid condition %q", condition)
+	}
+}
@@ -0,0 +1,79 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWaitTestContainer(state *types.ContainerState, healthcheck *container.HealthConfig) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{Name: "/web", State: state},
+		Config:            &container.Config{Healthcheck: healthcheck},
+	}
+}
+
+func TestCheckWaitConditionRunning(t *testing.T) {
+	done, err := checkWaitCondition(newWaitTestContainer(&types.ContainerState{Status: "created"}, nil), waitConditionRunning)
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	done, err = checkWaitCondition(newWaitTestContainer(&types.ContainerState{Running: true}, nil), waitConditionRunning)
+	assert.NoError(t, err)
+	assert.True(t, done)
+}
+
+func TestCheckWaitConditionHealthy(t *testing.T) {
+	healthcheck := &container.HealthConfig{Test: []string{"CMD", "true"}}
+	notStarted := "0001-01-01T00:00:00Z"
+
+	testCases := []struct {
+		state         *types.ContainerState
+		healthcheck   *container.HealthConfig
+		expectedDone  bool
+		expectedError string
+	}{
+		{
+			state:         &types.ContainerState{Running: true},
+			expectedError: "container web has no healthcheck",
+		},
+		{
+			state:         &types.ContainerState{Running: true},
+			healthcheck:   &container.HealthConfig{Test: []string{"NONE"}},
+			expectedError: "container web has no healthcheck",
+		},
+		{
+			state:       &types.ContainerState{StartedAt: notStarted},
+			healthcheck: healthcheck,
+		},
+		{
+			state:       &types.ContainerState{Running: true},
+			healthcheck: healthcheck,
+		},
+		{
+			state:       &types.ContainerState{Running: true, Health: &types.Health{Status: types.Starting}},
+			healthcheck: healthcheck,
+		},
+		{
+			state:        &types.ContainerState{Running: true, Health: &types.Health{Status: types.Healthy}},
+			healthcheck:  healthcheck,
+			expectedDone: true,
+		},
+		{
+			state:         &types.ContainerState{ExitCode: 3, StartedAt: "2017-06-01T10:00:00Z"},
+			healthcheck:   healthcheck,
+			expectedError: "container web exited with code 3 before becoming healthy",
+		},
+	}
+	for _, tc := range testCases {
+		done, err := checkWaitCondition(newWaitTestContainer(tc.state, tc.healthcheck), waitConditionHealthy)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expectedDone, done)
+	}
+}
@@ -40,13 +40,14 @@ UpdateStatus:
 {{- end }}
  Message:	{{ .UpdateStatusMessage }}
 {{- end }}
+{{- if .HasPlacement }}
 Placement:
 {{- if .TaskPlacementConstraints }}
  Constraints:	{{ .TaskPlacementConstraints }}
 {{- end }}
 {{- if .TaskPlacementPreferences }}
  Preferences:   {{ .TaskPlacementPreferences }}
-{{- end }}
+{{- end }}{{ end }}
 {{- if .HasUpdateConfig }}
 UpdateConfig:
  Parallelism:	{{ .UpdateParallelism }}
@@ -73,8 +74,28 @@ RollbackConfig:
  Max failure ratio: {{ .RollbackMaxFailureRatio }}
  Rollback order:    {{ .RollbackOrder }}
 {{- end }}
+{{- if .HasRestartPolicy }}
+RestartPolicy:
+ Condition:	{{ .RestartPolicyCondition }}
+{{- if .RestartPolicyDelay }}
+ Delay:		{{ .RestartPolicyDelay }}
+{{- end }}
+{{- if .RestartPolicyMaxAttempts }}
+ Max attempts:	{{ .RestartPolicyMaxAttempts }}
+{{- end }}
+{{- if .RestartPolicyWindow }}
+ Window:	{{ .RestartPolicyWindow }}
+{{- end }}{{ end }}
 ContainerSpec:
  Image:		{{ .ContainerImage }}
+{{- if .ContainerLabels }}
+ Labels:
+{{- range $k, $v := .ContainerLabels }}
+  {{ $k }}{{if $v }}={{ $v }}{{ end }}
+{{- end }}{{ end }}
+{{- if .ContainerCommand }}
+ Command:	{{ range $cmd := .ContainerCommand }}{{ $cmd }} {{ end }}
+{{- end -}}
 {{- if .ContainerArgs }}
  Args:		{{ range $arg := .ContainerArgs }}{{ $arg }} {{ end }}
 {{- end -}}
@@ -84,9 +105,55 @@ ContainerSpec:
 {{- if .ContainerWorkDir }}
  Dir:		{{ .ContainerWorkDir }}
 {{- end -}}
+{{- if .ContainerHostname }}
+ Hostname:	{{ .ContainerHostname }}
+{{- end -}}
 {{- if .ContainerUser }}
  User: {{ .ContainerUser }}
 {{- end }}
+{{- if .ContainerGroups }}
+ Groups:	{{ range $group := .ContainerGroups }}{{ $group }} {{ end }}
+{{- end -}}
+{{- if .ContainerStopSignal }}
+ Stop signal:	{{ .ContainerStopSignal }}
+{{- end -}}
+{{- if .ContainerStopGracePeriod }}
+ Stop grace period: {{ .ContainerStopGracePeriod }}
+{{- end -}}
+{{- if .ContainerTTY }}
+ TTY:		true
+{{- end -}}
+{{- if .ContainerOpenStdin }}
+ Open stdin:	true
+{{- end -}}
+{{- if .ContainerReadOnly }}
+ Read only:	true
+{{- end }}
+{{- if .HasPrivileges }}
+Privileges:
+{{- if .CredentialSpec }}
+ Credential spec:	{{ .CredentialSpec }}
+{{- end }}
+{{- if .SELinuxContext }}
+ SELinux context:	{{ .SELinuxContext }}
+{{- end }}{{ end }}
+{{- if .HasHealthcheck }}
+Healthcheck:
+{{- if .HealthcheckTest }}
+ Test:		{{ .HealthcheckTest }}
+{{- end }}
+{{- if .HealthcheckInterval }}
+ Interval:	{{ .HealthcheckInterval }}
+{{- end }}
+{{- if .HealthcheckTimeout }}
+ Timeout:	{{ .HealthcheckTimeout }}
+{{- end }}
+{{- if .HealthcheckStartPeriod }}
+ Start period:	{{ .HealthcheckStartPeriod }}
+{{- end }}
+{{- if .HealthcheckRetries }}
+ Retries:	{{ .HealthcheckRetries }}
+{{- end }}{{ end }}
 {{- if .ContainerMounts }}
 Mounts:
 {{- end }}
@@ -96,6 +163,18 @@ Mounts:
    ReadOnly = {{ $mount.ReadOnly }}
    Type = {{ $mount.Type }}
 {{- end -}}
+{{- if .ContainerSecrets }}
+Secrets:
+{{- range $secret := .ContainerSecrets }}
+  Name = {{ $secret.Name }}
+   Target = {{ $secret.Target }}
+{{- end }}{{ end -}}
+{{- if .ContainerConfigs }}
+Configs:
+{{- range $config := .ContainerConfigs }}
+  Name = {{ $config.Name }}
+   Target = {{ $config.Target }}
+{{- end }}{{ end -}}
 {{- if .HasResources }}
 Resources:
 {{- if .HasResourceReservations }}
@@ -114,10 +193,36 @@ Resources:
 {{- if .ResourceLimitMemory }}
   Memory:	{{ .ResourceLimitMemory }}
 {{- end }}{{ end }}{{ end }}
+{{- if .HasLogDriver }}
+Log Driver:
+ Name:		{{ .LogDriverName }}
+{{- if .LogDriverOptions }}
+ Options:
+{{- range $k, $v := .LogDriverOptions }}
+  {{ $k }}={{ $v }}
+{{- end }}{{ end }}{{ end }}
 {{- if .Networks }}
 Networks:
 {{- range $network := .Networks }} {{ $network }}{{ end }} {{ end }}
+{{- if .ContainerHosts }}
+Hosts:
+{{- range $host := .ContainerHosts }}
+ {{ $host }}
+{{- end }}{{ end }}
+{{- if .HasDNSConfig }}
+DNS:
+{{- if .DNSNameservers }}
+ Nameservers:	{{ range $ns := .DNSNameservers }}{{ $ns }} {{ end }}
+{{- end -}}
+{{- if .DNSSearch }}
+ Search:	{{ range $search := .DNSSearch }}{{ $search }} {{ end }}
+{{- end -}}
+{{- if .DNSOptions }}
+ Options:	{{ range $option := .DNSOptions }}{{ $option }} {{ end }}
+{{- end }}{{ end }}
+{{- if .EndpointMode }}
 Endpoint Mode:	{{ .EndpointMode }}
+{{- end }}
 {{- if .Ports }}
 Ports:
 {{- range $port := .Ports }}
@@ -138,20 +243,24 @@ func NewServiceFormat(source string) Format {
 	}
 }
 
-func resolveNetworks(service swarm.Service, getNetwork inspect.GetRefFunc) map[string]string {
-	networkNames := make(map[string]string)
-	for _, network := range service.Spec.TaskTemplate.Networks {
-		if resolved, _, err := getNetwork(network.Target); err == nil {
-			if resolvedNetwork, ok := resolved.(types.NetworkResource); ok {
-				networkNames[resolvedNetwork.ID] = resolvedNetwork.Name
-			}
-		}
+// ResolveNameFunc resolves the ID of a swarm object to its name. The type
+// of the object is given by t, as in idresolver.IDResolver.Resolve.
+type ResolveNameFunc func(t interface{}, id string) (string, error)
+
+// resolveName resolves id, falling back to the name stored along with the
+// reference, then to the ID itself, when it cannot be resolved.
+func resolveName(resolve ResolveNameFunc, t interface{}, id, fallback string) string {
+	if name, err := resolve(t, id); err == nil && name != "" && name != id {
+		return name
+	}
+	if fallback != "" {
+		return fallback
 	}
-	return networkNames
+	return id
 }
 
 // ServiceInspectWrite renders the context for a list of services
-func ServiceInspectWrite(ctx Context, refs []string, getRef, getNetwork inspect.GetRefFunc) error {
+func ServiceInspectWrite(ctx Context, refs []string, getRef inspect.GetRefFunc, resolve ResolveNameFunc) error {
 	if ctx.Format != serviceInspectPrettyTemplate {
 		return inspect.Inspect(ctx.Output, refs, string(ctx.Format), getRef)
 	}
@@ -165,7 +274,7 @@ func ServiceInspectWrite(ctx Context, refs []string, getRef, getNetwork inspect.
 			if !ok {
 				return errors.Errorf("got wrong object to inspect")
 			}
-			if err := format(&serviceInspectContext{Service: service, networkNames: resolveNetworks(service, getNetwork)}); err != nil {
+			if err := format(&serviceInspectContext{Service: service, resolve: resolve}); err != nil {
 				return err
 			}
 		}
@@ -178,9 +287,16 @@ type serviceInspectContext struct {
 	swarm.Service
 	subContext
 
-	// networkNames is a map from network IDs (as found in
-	// Networks[x].Target) to network names.
-	networkNames map[string]string
+	// resolve maps the IDs of networks, secrets and configs referenced by
+	// the service to their names.
+	resolve ResolveNameFunc
+}
+
+// serviceInspectReference is a secret or config used by a service, as shown
+// by the pretty printer
+type serviceInspectReference struct {
+	Name   string
+	Target string
 }
 
 func (ctx *serviceInspectContext) MarshalJSON() ([]byte, error) {
@@ -239,6 +355,10 @@ func (ctx *serviceInspectContext) UpdateStatusMessage() string {
 	return ctx.Service.UpdateStatus.Message
 }
 
+func (ctx *serviceInspectContext) HasPlacement() bool {
+	return len(ctx.TaskPlacementConstraints()) > 0 || len(ctx.TaskPlacementPreferences()) > 0
+}
+
 func (ctx *serviceInspectContext) TaskPlacementConstraints() []string {
 	if ctx.Service.Spec.TaskTemplate.Placement != nil {
 		return ctx.Service.Spec.TaskTemplate.Placement.Constraints
@@ -331,6 +451,35 @@ func (ctx *serviceInspectContext) RollbackOrder() string {
 	return ctx.Service.Spec.RollbackConfig.Order
 }
 
+func (ctx *serviceInspectContext) HasRestartPolicy() bool {
+	return ctx.Service.Spec.TaskTemplate.RestartPolicy != nil
+}
+
+func (ctx *serviceInspectContext) RestartPolicyCondition() swarm.RestartPolicyCondition {
+	return ctx.Service.Spec.TaskTemplate.RestartPolicy.Condition
+}
+
+func (ctx *serviceInspectContext) RestartPolicyDelay() string {
+	if ctx.Service.Spec.TaskTemplate.RestartPolicy.Delay == nil {
+		return ""
+	}
+	return ctx.Service.Spec.TaskTemplate.RestartPolicy.Delay.String()
+}
+
+func (ctx *serviceInspectContext) RestartPolicyMaxAttempts() uint64 {
+	if ctx.Service.Spec.TaskTemplate.RestartPolicy.MaxAttempts == nil {
+		return 0
+	}
+	return *ctx.Service.Spec.TaskTemplate.RestartPolicy.MaxAttempts
+}
+
+func (ctx *serviceInspectContext) RestartPolicyWindow() string {
+	if ctx.Service.Spec.TaskTemplate.RestartPolicy.Window == nil {
+		return ""
+	}
+	return ctx.Service.Spec.TaskTemplate.RestartPolicy.Window.String()
+}
+
 func (ctx *serviceInspectContext) ContainerImage() string {
 	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Image
 }
@@ -351,6 +500,150 @@ func (ctx *serviceInspectContext) ContainerUser() string {
 	return ctx.Service.Spec.TaskTemplate.ContainerSpec.User
 }
 
+func (ctx *serviceInspectContext) ContainerLabels() map[string]string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Labels
+}
+
+func (ctx *serviceInspectContext) ContainerCommand() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Command
+}
+
+func (ctx *serviceInspectContext) ContainerHostname() string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Hostname
+}
+
+func (ctx *serviceInspectContext) ContainerGroups() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Groups
+}
+
+func (ctx *serviceInspectContext) ContainerStopSignal() string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.StopSignal
+}
+
+func (ctx *serviceInspectContext) ContainerStopGracePeriod() string {
+	if ctx.Service.Spec.TaskTemplate.ContainerSpec.StopGracePeriod == nil {
+		return ""
+	}
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.StopGracePeriod.String()
+}
+
+func (ctx *serviceInspectContext) ContainerTTY() bool {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.TTY
+}
+
+func (ctx *serviceInspectContext) ContainerOpenStdin() bool {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.OpenStdin
+}
+
+func (ctx *serviceInspectContext) ContainerReadOnly() bool {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.ReadOnly
+}
+
+func (ctx *serviceInspectContext) HasPrivileges() bool {
+	return ctx.CredentialSpec() != "" || ctx.SELinuxContext() != ""
+}
+
+func (ctx *serviceInspectContext) CredentialSpec() string {
+	privileges := ctx.Service.Spec.TaskTemplate.ContainerSpec.Privileges
+	if privileges == nil || privileges.CredentialSpec == nil {
+		return ""
+	}
+	switch {
+	case privileges.CredentialSpec.File != "":
+		return "file://" + privileges.CredentialSpec.File
+	case privileges.CredentialSpec.Registry != "":
+		return "registry://" + privileges.CredentialSpec.Registry
+	}
+	return ""
+}
+
+func (ctx *serviceInspectContext) SELinuxContext() string {
+	privileges := ctx.Service.Spec.TaskTemplate.ContainerSpec.Privileges
+	if privileges == nil || privileges.SELinuxContext == nil {
+		return ""
+	}
+	selinux := privileges.SELinuxContext
+	if selinux.Disable {
+		return "disabled"
+	}
+	var parts []string
+	for _, part := range []struct{ key, value string }{
+		{"user", selinux.User},
+		{"role", selinux.Role},
+		{"type", selinux.Type},
+		{"level", selinux.Level},
+	} {
+		if part.value != "" {
+			parts = append(parts, part.key+"="+part.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (ctx *serviceInspectContext) HasHealthcheck() bool {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck != nil
+}
+
+func (ctx *serviceInspectContext) HealthcheckTest() string {
+	test := ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck.Test
+	if len(test) == 0 {
+		return ""
+	}
+	if test[0] == "NONE" {
+		return "disabled"
+	}
+	return strings.Join(test, " ")
+}
+
+func (ctx *serviceInspectContext) HealthcheckInterval() time.Duration {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck.Interval
+}
+
+func (ctx *serviceInspectContext) HealthcheckTimeout() time.Duration {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck.Timeout
+}
+
+func (ctx *serviceInspectContext) HealthcheckStartPeriod() time.Duration {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck.StartPeriod
+}
+
+func (ctx *serviceInspectContext) HealthcheckRetries() int {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Healthcheck.Retries
+}
+
+func (ctx *serviceInspectContext) ContainerHosts() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Hosts
+}
+
+func (ctx *serviceInspectContext) HasDNSConfig() bool {
+	dnsConfig := ctx.Service.Spec.TaskTemplate.ContainerSpec.DNSConfig
+	return dnsConfig != nil && (len(dnsConfig.Nameservers) > 0 || len(dnsConfig.Search) > 0 || len(dnsConfig.Options) > 0)
+}
+
+func (ctx *serviceInspectContext) DNSNameservers() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.DNSConfig.Nameservers
+}
+
+func (ctx *serviceInspectContext) DNSSearch() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.DNSConfig.Search
+}
+
+func (ctx *serviceInspectContext) DNSOptions() []string {
+	return ctx.Service.Spec.TaskTemplate.ContainerSpec.DNSConfig.Options
+}
+
+func (ctx *serviceInspectContext) HasLogDriver() bool {
+	return ctx.Service.Spec.TaskTemplate.LogDriver != nil && ctx.Service.Spec.TaskTemplate.LogDriver.Name != ""
+}
+
+func (ctx *serviceInspectContext) LogDriverName() string {
+	return ctx.Service.Spec.TaskTemplate.LogDriver.Name
+}
+
+func (ctx *serviceInspectContext) LogDriverOptions() map[string]string {
+	return ctx.Service.Spec.TaskTemplate.LogDriver.Options
+}
+
 func (ctx *serviceInspectContext) ContainerMounts() []mounttypes.Mount {
 	return ctx.Service.Spec.TaskTemplate.ContainerSpec.Mounts
 }
@@ -398,14 +691,34 @@ func (ctx *serviceInspectContext) ResourceLimitMemory() string {
 	return units.BytesSize(float64(ctx.Service.Spec.TaskTemplate.Resources.Limits.MemoryBytes))
 }
 
+func (ctx *serviceInspectContext) ContainerSecrets() []serviceInspectReference {
+	var out []serviceInspectReference
+	for _, secret := range ctx.Service.Spec.TaskTemplate.ContainerSpec.Secrets {
+		ref := serviceInspectReference{Name: resolveName(ctx.resolve, swarm.Secret{}, secret.SecretID, secret.SecretName)}
+		if secret.File != nil {
+			ref.Target = secret.File.Name
+		}
+		out = append(out, ref)
+	}
+	return out
+}
+
+func (ctx *serviceInspectContext) ContainerConfigs() []serviceInspectReference {
+	var out []serviceInspectReference
+	for _, config := range ctx.Service.Spec.TaskTemplate.ContainerSpec.Configs {
+		ref := serviceInspectReference{Name: resolveName(ctx.resolve, swarm.Config{}, config.ConfigID, config.ConfigName)}
+		if config.File != nil {
+			ref.Target = config.File.Name
+		}
+		out = append(out, ref)
+	}
+	return out
+}
+
 func (ctx *serviceInspectContext) Networks() []string {
 	var out []string
 	for _, n := range ctx.Service.Spec.TaskTemplate.Networks {
-		if name, ok := ctx.networkNames[n.Target]; ok {
-			out = append(out, name)
-		} else {
-			out = append(out, n.Target)
-		}
+		out = append(out, resolveName(ctx.resolve, types.NetworkResource{}, n.Target, ""))
 	}
 	return out
 }
@@ -425,9 +738,16 @@ func (ctx *serviceInspectContext) Ports() []swarm.PortConfig {
 const (
 	defaultServiceTableFormat = "table {{.ID}}\t{{.Name}}\t{{.Mode}}\t{{.Replicas}}\t{{.Image}}\t{{.Ports}}"
 
-	serviceIDHeader = "ID"
-	modeHeader      = "MODE"
-	replicasHeader  = "REPLICAS"
+	serviceUpdateStatusColumns = "\t{{.UpdateState}}\t{{.UpdateStarted}}\t{{.UpdateMessage}}"
+	serviceImageStatusColumn   = "\t{{.ImageStatus}}"
+
+	serviceIDHeader     = "ID"
+	modeHeader          = "MODE"
+	replicasHeader      = "REPLICAS"
+	updateStateHeader   = "UPDATE STATE"
+	updateStartedHeader = "UPDATE STARTED"
+	updateMessageHeader = "UPDATE MESSAGE"
+	imageStatusHeader   = "IMAGE STATUS"
 )
 
 // NewServiceListFormat returns a Format for rendering using a service Context
@@ -447,17 +767,37 @@ func NewServiceListFormat(source string, quiet bool) Format {
 	return Format(source)
 }
 
-// ServiceListInfo stores the information about mode and replicas to be used by template
+// NewServiceListTableFormat returns the default table Format for services,
+// extended with the update status and image status columns if requested
+func NewServiceListTableFormat(updateStatus, imageStatus bool) Format {
+	format := defaultServiceTableFormat
+	if updateStatus {
+		format += serviceUpdateStatusColumns
+	}
+	if imageStatus {
+		format += serviceImageStatusColumn
+	}
+	return Format(format)
+}
+
+// ServiceListInfo stores the information about mode, replicas and image
+// status to be used by template
 type ServiceListInfo struct {
-	Mode     string
-	Replicas string
+	Mode        string
+	Replicas    string
+	ImageStatus string
 }
 
 // ServiceListWrite writes the context
 func ServiceListWrite(ctx Context, services []swarm.Service, info map[string]ServiceListInfo) error {
 	render := func(format func(subContext subContext) error) error {
 		for _, service := range services {
-			serviceCtx := &serviceContext{service: service, mode: info[service.ID].Mode, replicas: info[service.ID].Replicas}
+			serviceCtx := &serviceContext{
+				service:     service,
+				mode:        info[service.ID].Mode,
+				replicas:    info[service.ID].Replicas,
+				imageStatus: info[service.ID].ImageStatus,
+			}
 			if err := format(serviceCtx); err != nil {
 				return err
 			}
@@ -472,15 +812,21 @@ func ServiceListWrite(ctx Context, services []swarm.Service, info map[string]Ser
 		"Replicas": replicasHeader,
 		"Image":    imageHeader,
 		"Ports":    portsHeader,
+
+		"UpdateState":   updateStateHeader,
+		"UpdateStarted": updateStartedHeader,
+		"UpdateMessage": updateMessageHeader,
+		"ImageStatus":   imageStatusHeader,
 	}
 	return ctx.Write(&serviceCtx, render)
 }
 
 type serviceContext struct {
 	HeaderContext
-	service  swarm.Service
-	mode     string
-	replicas string
+	service     swarm.Service
+	mode        string
+	replicas    string
+	imageStatus string
 }
 
 func (c *serviceContext) MarshalJSON() ([]byte, error) {
@@ -533,3 +879,28 @@ func (c *serviceContext) Ports() string {
 	}
 	return strings.Join(ports, ",")
 }
+
+func (c *serviceContext) UpdateState() string {
+	if c.service.UpdateStatus == nil {
+		return ""
+	}
+	return string(c.service.UpdateStatus.State)
+}
+
+func (c *serviceContext) UpdateStarted() string {
+	if c.service.UpdateStatus == nil || c.service.UpdateStatus.StartedAt == nil {
+		return ""
+	}
+	return units.HumanDuration(time.Since(*c.service.UpdateStatus.StartedAt)) + " ago"
+}
+
+func (c *serviceContext) UpdateMessage() string {
+	if c.service.UpdateStatus == nil {
+		return ""
+	}
+	return c.service.UpdateStatus.Message
+}
+
+func (c *serviceContext) ImageStatus() string {
+	return c.imageStatus
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/stretchr/testify/assert"
@@ -190,8 +191,8 @@ func TestServiceContextWriteJSON(t *testing.T) {
 		},
 	}
 	expectedJSONs := []map[string]interface{}{
-		{"ID": "id_baz", "Name": "baz", "Mode": "global", "Replicas": "2/4", "Image": "", "Ports": "*:80->8080/tcp"},
-		{"ID": "id_bar", "Name": "bar", "Mode": "replicated", "Replicas": "2/4", "Image": "", "Ports": "*:80->8080/tcp"},
+		{"ID": "id_baz", "Name": "baz", "Mode": "global", "Replicas": "2/4", "Image": "", "Ports": "*:80->8080/tcp", "UpdateState": "", "UpdateStarted": "", "UpdateMessage": "", "ImageStatus": ""},
+		{"ID": "id_bar", "Name": "bar", "Mode": "replicated", "Replicas": "2/4", "Image": "", "Ports": "*:80->8080/tcp", "UpdateState": "", "UpdateStarted": "", "UpdateMessage": "", "ImageStatus": ""},
 	}
 
 	out := bytes.NewBufferString("")
@@ -237,3 +238,36 @@ func TestServiceContextWriteJSONField(t *testing.T) {
 		assert.Equal(t, services[i].Spec.Name, s)
 	}
 }
+
+func TestServiceContextWriteUpdateAndImageStatus(t *testing.T) {
+	startedAt := time.Now().Add(-2 * time.Hour)
+	services := []swarm.Service{
+		{
+			ID:   "id_baz",
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "baz"}},
+			UpdateStatus: &swarm.UpdateStatus{
+				State:     swarm.UpdateStatePaused,
+				StartedAt: &startedAt,
+				Message:   "update paused due to failure",
+			},
+		},
+		{ID: "id_bar", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "bar"}}},
+	}
+	info := map[string]ServiceListInfo{
+		"id_baz": {ImageStatus: "stale"},
+		"id_bar": {ImageStatus: "current"},
+	}
+	out := bytes.NewBufferString("")
+	format := "table {{.Name}}\t{{.UpdateState}}\t{{.UpdateStarted}}\t{{.UpdateMessage}}\t{{.ImageStatus}}"
+	err := ServiceListWrite(Context{Format: NewServiceListFormat(format, false), Output: out}, services, info)
+	assert.NoError(t, err)
+	assert.Equal(t, `NAME                UPDATE STATE        UPDATE STARTED      UPDATE MESSAGE                 IMAGE STATUS
+baz                 paused              2 hours ago         update paused due to failure   stale
+bar                                                                                        current
+`, out.String())
+}
+
+func TestNewServiceListTableFormat(t *testing.T) {
+	assert.Equal(t, Format(defaultServiceTableFormat), NewServiceListTableFormat(false, false))
+	assert.Equal(t, Format(defaultServiceTableFormat+"\t{{.UpdateState}}\t{{.UpdateStarted}}\t{{.UpdateMessage}}\t{{.ImageStatus}}"), NewServiceListTableFormat(true, true))
+}
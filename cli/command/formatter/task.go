@@ -2,6 +2,8 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,12 +16,16 @@ import (
 
 const (
 	defaultTaskTableFormat = "table {{.ID}}\t{{.Name}}\t{{.Image}}\t{{.Node}}\t{{.DesiredState}}\t{{.CurrentState}}\t{{.Error}}\t{{.Ports}}"
+	taskDetailsTableFormat = "table {{.ID}}\t{{.Name}}\t{{.Node}}\t{{.CurrentState}}\t{{.ExitCode}}\t{{.Created}}\t{{.Duration}}\t{{.Chain}}\t{{.FullError}}"
 
 	nodeHeader         = "NODE"
 	taskIDHeader       = "ID"
 	desiredStateHeader = "DESIRED STATE"
 	currentStateHeader = "CURRENT STATE"
 	errorHeader        = "ERROR"
+	exitCodeHeader     = "EXIT CODE"
+	durationHeader     = "DURATION"
+	chainHeader        = "HISTORY"
 
 	maxErrLength = 30
 )
@@ -41,11 +47,28 @@ func NewTaskFormat(source string, quiet bool) Format {
 	return Format(source)
 }
 
+// NewTaskDetailsFormat returns the table Format showing the lifecycle of
+// each task: its exit code, timeline, slot history and full error
+func NewTaskDetailsFormat(quiet bool) Format {
+	if quiet {
+		return defaultQuietFormat
+	}
+	return taskDetailsTableFormat
+}
+
 // TaskWrite writes the context
-func TaskWrite(ctx Context, tasks []swarm.Task, names map[string]string, nodes map[string]string) error {
+func TaskWrite(ctx Context, tasks []swarm.Task, names map[string]string, nodes map[string]string, networks map[string]string) error {
+	chains := taskChains(tasks)
 	render := func(format func(subContext subContext) error) error {
 		for _, task := range tasks {
-			taskCtx := &taskContext{trunc: ctx.Trunc, task: task, name: names[task.ID], node: nodes[task.ID]}
+			taskCtx := &taskContext{
+				trunc:    ctx.Trunc,
+				task:     task,
+				name:     names[task.ID],
+				node:     nodes[task.ID],
+				networks: networks,
+				chain:    chains[task.ID],
+			}
 			if err := format(taskCtx); err != nil {
 				return err
 			}
@@ -62,10 +85,72 @@ func TaskWrite(ctx Context, tasks []swarm.Task, names map[string]string, nodes m
 		"CurrentState": currentStateHeader,
 		"Error":        errorHeader,
 		"Ports":        portsHeader,
+		"ExitCode":     exitCodeHeader,
+		"Created":      createdSinceHeader,
+		"Duration":     durationHeader,
+		"Chain":        chainHeader,
+		"FullError":    errorHeader,
+		"Networks":     networksHeader,
 	}
 	return ctx.Write(&taskCtx, render)
 }
 
+// taskChains groups the tasks by slot, or by node for global services, and
+// returns the history of each slot keyed by the ID of its most recent task.
+// The history lists the state of every task of the slot, oldest first, so
+// that a crash loop shows up as a run of failures.
+func taskChains(tasks []swarm.Task) map[string]string {
+	type slotKey struct {
+		serviceID string
+		slot      int
+		nodeID    string
+	}
+	slots := map[slotKey][]swarm.Task{}
+	var keys []slotKey
+	for _, task := range tasks {
+		key := slotKey{serviceID: task.ServiceID, slot: task.Slot}
+		if task.Slot == 0 {
+			key.nodeID = task.NodeID
+		}
+		if _, ok := slots[key]; !ok {
+			keys = append(keys, key)
+		}
+		slots[key] = append(slots[key], task)
+	}
+
+	chains := map[string]string{}
+	for _, key := range keys {
+		slotTasks := slots[key]
+		sort.SliceStable(slotTasks, func(i, j int) bool {
+			return slotTasks[i].Meta.CreatedAt.Before(slotTasks[j].Meta.CreatedAt)
+		})
+		states := make([]string, 0, len(slotTasks))
+		for _, task := range slotTasks {
+			state := string(task.Status.State)
+			if exitCode, ok := taskExitCode(task); ok {
+				state = fmt.Sprintf("%s(%d)", state, exitCode)
+			}
+			states = append(states, state)
+		}
+		latest := slotTasks[len(slotTasks)-1]
+		chains[latest.ID] = strings.Join(states, " -> ")
+	}
+	return chains
+}
+
+// taskExitCode returns the exit code of the container of a task, if the
+// container has exited.
+func taskExitCode(task swarm.Task) (int, bool) {
+	if task.Status.ContainerStatus.ContainerID == "" {
+		return 0, false
+	}
+	switch task.Status.State {
+	case swarm.TaskStateComplete, swarm.TaskStateFailed, swarm.TaskStateShutdown:
+		return task.Status.ContainerStatus.ExitCode, true
+	}
+	return 0, false
+}
+
 type taskHeaderContext map[string]string
 
 type taskContext struct {
@@ -74,6 +159,9 @@ type taskContext struct {
 	task  swarm.Task
 	name  string
 	node  string
+	// networks maps network IDs to names
+	networks map[string]string
+	chain    string
 }
 
 func (c *taskContext) MarshalJSON() ([]byte, error) {
@@ -148,3 +236,54 @@ func (c *taskContext) Ports() string {
 	}
 	return strings.Join(ports, ",")
 }
+
+func (c *taskContext) ExitCode() string {
+	if exitCode, ok := taskExitCode(c.task); ok {
+		return strconv.Itoa(exitCode)
+	}
+	return ""
+}
+
+func (c *taskContext) Created() string {
+	if c.task.Meta.CreatedAt.IsZero() {
+		return ""
+	}
+	return strings.ToLower(units.HumanDuration(time.Since(c.task.Meta.CreatedAt))) + " ago"
+}
+
+// Duration returns how long the task has existed, up to the moment it
+// reached a final state.
+func (c *taskContext) Duration() string {
+	if c.task.Meta.CreatedAt.IsZero() {
+		return ""
+	}
+	end := time.Now()
+	switch c.task.Status.State {
+	case swarm.TaskStateComplete, swarm.TaskStateFailed, swarm.TaskStateShutdown, swarm.TaskStateRejected:
+		end = c.task.Status.Timestamp
+	}
+	return strings.ToLower(units.HumanDuration(end.Sub(c.task.Meta.CreatedAt)))
+}
+
+func (c *taskContext) Chain() string {
+	return c.chain
+}
+
+func (c *taskContext) FullError() string {
+	if len(c.task.Status.Err) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\"%s\"", c.task.Status.Err)
+}
+
+func (c *taskContext) Networks() string {
+	names := []string{}
+	for _, network := range c.task.Spec.Networks {
+		if name, ok := c.networks[network.Target]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, network.Target)
+		}
+	}
+	return strings.Join(names, ",")
+}
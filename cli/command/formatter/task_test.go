@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/stretchr/testify/assert"
@@ -74,7 +75,7 @@ foobar_bar foo2
 		}
 		out := bytes.NewBufferString("")
 		testcase.context.Output = out
-		err := TaskWrite(testcase.context, tasks, names, nodes)
+		err := TaskWrite(testcase.context, tasks, names, nodes, map[string]string{})
 		if err != nil {
 			assert.EqualError(t, err, testcase.expected)
 		} else {
@@ -93,7 +94,7 @@ func TestTaskContextWriteJSONField(t *testing.T) {
 		"taskID2": "foobar_bar",
 	}
 	out := bytes.NewBufferString("")
-	err := TaskWrite(Context{Format: "{{json .ID}}", Output: out}, tasks, names, map[string]string{})
+	err := TaskWrite(Context{Format: "{{json .ID}}", Output: out}, tasks, names, map[string]string{}, map[string]string{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -105,3 +106,75 @@ func TestTaskContextWriteJSONField(t *testing.T) {
 		assert.Equal(t, tasks[i].ID, s)
 	}
 }
+
+func TestTaskContextWriteDetails(t *testing.T) {
+	now := time.Now()
+	tasks := []swarm.Task{
+		{
+			ID:        "taskID3",
+			ServiceID: "service",
+			Slot:      1,
+			Meta:      swarm.Meta{CreatedAt: now.Add(-10 * time.Minute)},
+			Status: swarm.TaskStatus{
+				State:           swarm.TaskStateRunning,
+				Timestamp:       now.Add(-10 * time.Minute),
+				ContainerStatus: swarm.ContainerStatus{ContainerID: "container3"},
+			},
+		},
+		{
+			ID:        "taskID2",
+			ServiceID: "service",
+			Slot:      1,
+			Meta:      swarm.Meta{CreatedAt: now.Add(-20 * time.Minute)},
+			Status: swarm.TaskStatus{
+				State:           swarm.TaskStateFailed,
+				Timestamp:       now.Add(-15 * time.Minute),
+				Err:             "task: non-zero exit (137): killed by the out of memory killer",
+				ContainerStatus: swarm.ContainerStatus{ContainerID: "container2", ExitCode: 137},
+			},
+		},
+		{
+			ID:        "taskID1",
+			ServiceID: "service",
+			Slot:      1,
+			Meta:      swarm.Meta{CreatedAt: now.Add(-30 * time.Minute)},
+			Status: swarm.TaskStatus{
+				State:           swarm.TaskStateFailed,
+				Timestamp:       now.Add(-25 * time.Minute),
+				Err:             "task: non-zero exit (1)",
+				ContainerStatus: swarm.ContainerStatus{ContainerID: "container1", ExitCode: 1},
+			},
+		},
+	}
+	names := map[string]string{
+		"taskID3": "web.1",
+		"taskID2": " \\_ web.1",
+		"taskID1": " \\_ web.1",
+	}
+	out := bytes.NewBufferString("")
+	format := "table {{.ID}}\t{{.Name}}\t{{.ExitCode}}\t{{.Created}}\t{{.Duration}}\t{{.Chain}}\t{{.FullError}}"
+	err := TaskWrite(Context{Format: NewTaskFormat(format, false), Output: out}, tasks, names, map[string]string{}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, `ID                  NAME                EXIT CODE           CREATED             DURATION            HISTORY                               ERROR
+taskID3             web.1                                   10 minutes ago      10 minutes          failed(1) -> failed(137) -> running   
+taskID2              \_ web.1           137                 20 minutes ago      5 minutes                                                 "task: non-zero exit (137): killed by the out of memory killer"
+taskID1              \_ web.1           1                   30 minutes ago      5 minutes                                                 "task: non-zero exit (1)"
+`, out.String())
+}
+
+func TestTaskContextWriteNetworks(t *testing.T) {
+	tasks := []swarm.Task{
+		{
+			ID: "taskID1",
+			Spec: swarm.TaskSpec{Networks: []swarm.NetworkAttachmentConfig{
+				{Target: "networkID1"},
+				{Target: "networkID2"},
+			}},
+		},
+	}
+	networks := map[string]string{"networkID1": "frontend"}
+	out := bytes.NewBufferString("")
+	err := TaskWrite(Context{Format: NewTaskFormat("{{.ID}} {{.Networks}}", false), Output: out}, tasks, map[string]string{}, map[string]string{}, networks)
+	assert.NoError(t, err)
+	assert.Equal(t, "taskID1 frontend,networkID2\n", out.String())
+}
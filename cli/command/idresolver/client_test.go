@@ -11,6 +11,9 @@ type fakeClient struct {
 	client.Client
 	nodeInspectFunc    func(string) (swarm.Node, []byte, error)
 	serviceInspectFunc func(string) (swarm.Service, []byte, error)
+	networkListFunc    func() ([]types.NetworkResource, error)
+	secretListFunc     func() ([]swarm.Secret, error)
+	configListFunc     func() ([]swarm.Config, error)
 }
 
 func (cli *fakeClient) NodeInspectWithRaw(ctx context.Context, nodeID string) (swarm.Node, []byte, error) {
@@ -26,3 +29,24 @@ func (cli *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID stri
 	}
 	return swarm.Service{}, []byte{}, nil
 }
+
+func (cli *fakeClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	if cli.networkListFunc != nil {
+		return cli.networkListFunc()
+	}
+	return []types.NetworkResource{}, nil
+}
+
+func (cli *fakeClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	if cli.secretListFunc != nil {
+		return cli.secretListFunc()
+	}
+	return []swarm.Secret{}, nil
+}
+
+func (cli *fakeClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	if cli.configListFunc != nil {
+		return cli.configListFunc()
+	}
+	return []swarm.Config{}, nil
+}
@@ -1,8 +1,11 @@
 package idresolver
 
 import (
+	"fmt"
+
 	"golang.org/x/net/context"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
@@ -14,6 +17,9 @@ type IDResolver struct {
 	client    client.APIClient
 	noResolve bool
 	cache     map[string]string
+	// listed records the kinds of objects which were fetched with a single
+	// list call and stored in the cache
+	listed map[string]bool
 }
 
 // New creates a new IDResolver.
@@ -22,6 +28,7 @@ func New(client client.APIClient, noResolve bool) *IDResolver {
 		client:    client,
 		noResolve: noResolve,
 		cache:     make(map[string]string),
+		listed:    make(map[string]bool),
 	}
 }
 
@@ -45,12 +52,77 @@ func (r *IDResolver) get(ctx context.Context, t interface{}, id string) (string,
 			return id, nil
 		}
 		return service.Spec.Annotations.Name, nil
+	case types.NetworkResource:
+		return r.getListed(ctx, "network", id, r.listNetworks)
+	case swarm.Secret:
+		return r.getListed(ctx, "secret", id, r.listSecrets)
+	case swarm.Config:
+		return r.getListed(ctx, "config", id, r.listConfigs)
 	default:
 		return "", errors.Errorf("unsupported type")
 	}
 
 }
 
+// listError is the error of a failed list call of getListed
+type listError struct {
+	kind string
+	err  error
+}
+
+func (e listError) Error() string {
+	return fmt.Sprintf("failed to list %ss: %v", e.kind, e.err)
+}
+
+// getListed resolves an ID of a kind of objects which are all fetched at
+// once on the first lookup, rather than inspected one by one. When the list
+// call fails, a listError is returned and the next lookup lists again.
+func (r *IDResolver) getListed(ctx context.Context, kind, id string, list func(context.Context) error) (string, error) {
+	if !r.listed[kind] {
+		if err := list(ctx); err != nil {
+			return "", listError{kind: kind, err: err}
+		}
+		r.listed[kind] = true
+	}
+	if name, ok := r.cache[id]; ok {
+		return name, nil
+	}
+	return id, nil
+}
+
+func (r *IDResolver) listNetworks(ctx context.Context) error {
+	networks, err := r.client.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		r.cache[network.ID] = network.Name
+	}
+	return nil
+}
+
+func (r *IDResolver) listSecrets(ctx context.Context) error {
+	secrets, err := r.client.SecretList(ctx, types.SecretListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		r.cache[secret.ID] = secret.Spec.Annotations.Name
+	}
+	return nil
+}
+
+func (r *IDResolver) listConfigs(ctx context.Context) error {
+	configs, err := r.client.ConfigList(ctx, types.ConfigListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		r.cache[config.ID] = config.Spec.Annotations.Name
+	}
+	return nil
+}
+
 // Resolve will attempt to resolve an ID to a Name by querying the manager.
 // Results are stored into a cache.
 // If the `-n` flag is used in the command-line, resolution is disabled.
@@ -62,6 +134,11 @@ func (r *IDResolver) Resolve(ctx context.Context, t interface{}, id string) (str
 		return name, nil
 	}
 	name, err := r.get(ctx, t, id)
+	if err, ok := err.(listError); ok {
+		// the ID is not cached, so it is resolved again on the next lookup
+		logrus.Debugf("failed to resolve %s: %v", id, err)
+		return id, nil
+	}
 	if err != nil {
 		return "", err
 	}
@@ -3,6 +3,7 @@ package idresolver
 import (
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	// Import builders to get the builder function as package function
 	. "github.com/docker/cli/cli/internal/test/builders"
@@ -142,3 +143,77 @@ func TestResolveService(t *testing.T) {
 		assert.Equal(t, tc.expectedID, id)
 	}
 }
+
+func TestResolveNetworkListsOnce(t *testing.T) {
+	listCounter := 0
+	cli := &fakeClient{
+		networkListFunc: func() ([]types.NetworkResource, error) {
+			listCounter++
+			return []types.NetworkResource{
+				{ID: "networkID1", Name: "frontend"},
+				{ID: "networkID2", Name: "backend"},
+			}, nil
+		},
+	}
+
+	idResolver := New(cli, false)
+	ctx := context.Background()
+	for id, expected := range map[string]string{
+		"networkID1": "frontend",
+		"networkID2": "backend",
+		"networkID3": "networkID3",
+	} {
+		name, err := idResolver.Resolve(ctx, types.NetworkResource{}, id)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, name)
+	}
+	assert.Equal(t, 1, listCounter)
+}
+
+func TestResolveSecretAndConfig(t *testing.T) {
+	cli := &fakeClient{
+		secretListFunc: func() ([]swarm.Secret, error) {
+			return []swarm.Secret{*Secret(SecretID("secretID"), SecretName("db-password"))}, nil
+		},
+		configListFunc: func() ([]swarm.Config, error) {
+			return []swarm.Config{*Config(ConfigID("configID"), ConfigName("nginx.conf"))}, nil
+		},
+	}
+
+	idResolver := New(cli, false)
+	ctx := context.Background()
+	name, err := idResolver.Resolve(ctx, swarm.Secret{}, "secretID")
+	assert.NoError(t, err)
+	assert.Equal(t, "db-password", name)
+
+	name, err = idResolver.Resolve(ctx, swarm.Config{}, "configID")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx.conf", name)
+}
+
+func TestResolveListError(t *testing.T) {
+	listCounter := 0
+	cli := &fakeClient{
+		secretListFunc: func() ([]swarm.Secret, error) {
+			listCounter++
+			if listCounter == 1 {
+				return nil, errors.Errorf("error listing secrets")
+			}
+			return []swarm.Secret{*Secret(SecretID("secretID2"), SecretName("db-password"))}, nil
+		},
+	}
+
+	idResolver := New(cli, false)
+	ctx := context.Background()
+	name, err := idResolver.Resolve(ctx, swarm.Secret{}, "secretID1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secretID1", name)
+	_, cached := idResolver.cache["secretID1"]
+	assert.False(t, cached)
+
+	// the failed list call is not cached, so the next lookup lists again
+	name, err = idResolver.Resolve(ctx, swarm.Secret{}, "secretID2")
+	assert.NoError(t, err)
+	assert.Equal(t, "db-password", name)
+	assert.Equal(t, 2, listCounter)
+}
@@ -1,23 +1,28 @@
 package node
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
 type listOptions struct {
-	quiet  bool
-	format string
-	filter opts.FilterOpt
+	quiet       bool
+	format      string
+	filter      opts.FilterOpt
+	constraints opts.ListOpts
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
-	options := listOptions{filter: opts.NewFilterOpt()}
+	options := listOptions{filter: opts.NewFilterOpt(), constraints: opts.NewListOpts(nil)}
 
 	cmd := &cobra.Command{
 		Use:     "ls [OPTIONS]",
@@ -32,6 +37,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print nodes using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.Var(&options.constraints, "constraint", "Only show nodes satisfying a placement constraint")
 
 	return cmd
 }
@@ -47,6 +53,13 @@ func runList(dockerCli command.Cli, options listOptions) error {
 		return err
 	}
 
+	if constraints := options.constraints.GetAll(); len(constraints) > 0 {
+		nodes, err = filterByConstraints(dockerCli, nodes, constraints, options.quiet)
+		if err != nil {
+			return err
+		}
+	}
+
 	info := types.Info{}
 	if len(nodes) > 0 && !options.quiet {
 		// only non-empty nodes and not quiet, should we call /info api
@@ -70,3 +83,24 @@ func runList(dockerCli command.Cli, options listOptions) error {
 	}
 	return formatter.NodeWrite(nodesCtx, nodes, info)
 }
+
+// filterByConstraints returns the nodes on which a task with the given
+// placement constraints could be scheduled. Unless quiet is set, the reasons
+// the other nodes were rejected are printed on stderr.
+func filterByConstraints(dockerCli command.Cli, nodes []swarm.Node, constraints []string, quiet bool) ([]swarm.Node, error) {
+	results, err := CheckPlacement(nodes, nil, constraints, nil)
+	if err != nil {
+		return nil, err
+	}
+	eligible := []swarm.Node{}
+	for _, result := range results {
+		if result.Eligible() {
+			eligible = append(eligible, result.Node)
+			continue
+		}
+		if !quiet {
+			fmt.Fprintf(dockerCli.Err(), "%s rejected: %s\n", result.Node.Description.Hostname, strings.Join(result.Reasons, ", "))
+		}
+	}
+	return eligible, nil
+}
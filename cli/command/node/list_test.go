@@ -160,3 +160,24 @@ func TestNodeListFormat(t *testing.T) {
 	assert.Contains(t, buf.String(), `nodeHostname1: Leader`)
 	assert.Contains(t, buf.String(), `nodeHostname2: Reachable`)
 }
+
+func TestNodeListConstraint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				*Node(NodeID("nodeID1"), Hostname("nodeHostname1"), NodeLabels(map[string]string{"zone": "east"})),
+				*Node(NodeID("nodeID2"), Hostname("nodeHostname2"), NodeLabels(map[string]string{"zone": "west"})),
+			}, nil
+		},
+	}, buf)
+	cli.SetErr(errBuf)
+	cli.SetConfigfile(&configfile.ConfigFile{})
+	cmd := newListCommand(cli)
+	cmd.Flags().Set("constraint", "node.labels.zone==east")
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "nodeHostname1")
+	assert.NotContains(t, buf.String(), "nodeHostname2")
+	assert.Equal(t, "nodeHostname2 rejected: constraint node.labels.zone==east: node.labels.zone is \"west\"\n", errBuf.String())
+}
@@ -0,0 +1,175 @@
+package node
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+var (
+	constraintKeyPattern   = regexp.MustCompile(`^(?i)[a-z_][a-z0-9\-_.]+$`)
+	constraintValuePattern = regexp.MustCompile(`^(?i)[a-z0-9:\-_\s\.\*\(\)\?\+\[\]\\\^\$\|\/]+$`)
+)
+
+// constraint is a parsed placement constraint, such as node.role==manager
+type constraint struct {
+	expr  string
+	key   string
+	value string
+	equal bool
+}
+
+// parseConstraints parses placement constraints with the same syntax the
+// swarm scheduler accepts.
+func parseConstraints(exprs []string) ([]constraint, error) {
+	constraints := make([]constraint, 0, len(exprs))
+	for _, expr := range exprs {
+		c, err := parseConstraint(expr)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+func parseConstraint(expr string) (constraint, error) {
+	for _, operator := range []string{"==", "!="} {
+		parts := strings.SplitN(expr, operator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if !constraintKeyPattern.MatchString(key) {
+			return constraint{}, errors.Errorf("invalid constraint %q: key %q is invalid", expr, key)
+		}
+		if !constraintValuePattern.MatchString(value) {
+			return constraint{}, errors.Errorf("invalid constraint %q: value %q is invalid", expr, value)
+		}
+		return constraint{expr: expr, key: key, value: value, equal: operator == "=="}, nil
+	}
+	return constraint{}, errors.Errorf("invalid constraint %q: expected an expression of the form key==value or key!=value", expr)
+}
+
+// check returns why the node does not satisfy the constraint, or an empty
+// string if it does.
+func (c constraint) check(node swarm.Node) string {
+	var (
+		actual string
+		found  = true
+	)
+	switch key := strings.ToLower(c.key); {
+	case key == "node.id":
+		actual = node.ID
+	case key == "node.hostname":
+		actual = node.Description.Hostname
+	case key == "node.role":
+		actual = string(node.Spec.Role)
+	case key == "node.platform.os":
+		actual = node.Description.Platform.OS
+	case key == "node.platform.arch":
+		actual = node.Description.Platform.Architecture
+	case strings.HasPrefix(key, "node.labels."):
+		actual, found = node.Spec.Labels[c.key[len("node.labels."):]]
+	case strings.HasPrefix(key, "engine.labels."):
+		actual, found = node.Description.Engine.Labels[c.key[len("engine.labels."):]]
+	default:
+		return fmt.Sprintf("constraint %s: unknown key %s", c.expr, c.key)
+	}
+
+	// like the scheduler, a missing label only satisfies != constraints
+	if !found {
+		if c.equal {
+			return fmt.Sprintf("constraint %s: %s is not set", c.expr, c.key)
+		}
+		return ""
+	}
+	if strings.EqualFold(actual, c.value) != c.equal {
+		return fmt.Sprintf("constraint %s: %s is %q", c.expr, c.key, actual)
+	}
+	return ""
+}
+
+// PlacementResult is the outcome of checking whether the tasks of a service
+// can be scheduled on a node.
+type PlacementResult struct {
+	Node swarm.Node
+	// Reasons lists why the node was rejected; it is empty if the node
+	// qualifies.
+	Reasons []string
+}
+
+// Eligible returns whether the node qualifies
+func (r PlacementResult) Eligible() bool {
+	return len(r.Reasons) == 0
+}
+
+// CheckPlacement simulates the placement of a task on each node. It
+// evaluates the placement constraints, the availability of the node, and
+// the resource reservations against the resources left on the node by the
+// tasks already running there.
+func CheckPlacement(nodes []swarm.Node, tasks []swarm.Task, constraintExprs []string, reservations *swarm.Resources) ([]PlacementResult, error) {
+	constraints, err := parseConstraints(constraintExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := map[string]swarm.Resources{}
+	for _, task := range tasks {
+		if task.NodeID == "" || task.DesiredState != swarm.TaskStateRunning {
+			continue
+		}
+		if task.Spec.Resources == nil || task.Spec.Resources.Reservations == nil {
+			continue
+		}
+		r := reserved[task.NodeID]
+		r.NanoCPUs += task.Spec.Resources.Reservations.NanoCPUs
+		r.MemoryBytes += task.Spec.Resources.Reservations.MemoryBytes
+		reserved[task.NodeID] = r
+	}
+
+	results := make([]PlacementResult, 0, len(nodes))
+	for _, node := range nodes {
+		result := PlacementResult{Node: node}
+		if node.Status.State != swarm.NodeStateReady {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("node is %s", node.Status.State))
+		}
+		if node.Spec.Availability != swarm.NodeAvailabilityActive {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("availability is %s", node.Spec.Availability))
+		}
+		for _, c := range constraints {
+			if reason := c.check(node); reason != "" {
+				result.Reasons = append(result.Reasons, reason)
+			}
+		}
+		if reservations != nil {
+			result.Reasons = append(result.Reasons, checkResources(node, reserved[node.ID], *reservations)...)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func checkResources(node swarm.Node, reserved, requested swarm.Resources) []string {
+	var reasons []string
+	availableCPUs := node.Description.Resources.NanoCPUs - reserved.NanoCPUs
+	if requested.NanoCPUs > 0 && requested.NanoCPUs > availableCPUs {
+		reasons = append(reasons, fmt.Sprintf("insufficient CPU: %s requested, %s available",
+			formatCPUs(requested.NanoCPUs), formatCPUs(availableCPUs)))
+	}
+	availableMemory := node.Description.Resources.MemoryBytes - reserved.MemoryBytes
+	if requested.MemoryBytes > 0 && requested.MemoryBytes > availableMemory {
+		reasons = append(reasons, fmt.Sprintf("insufficient memory: %s requested, %s available",
+			units.BytesSize(float64(requested.MemoryBytes)), units.BytesSize(float64(availableMemory))))
+	}
+	return reasons
+}
+
+func formatCPUs(nanoCPUs int64) string {
+	return fmt.Sprintf("%g", float64(nanoCPUs)/1e9)
+}
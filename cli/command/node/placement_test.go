@@ -0,0 +1,81 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/cli/internal/test/builders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, expr := range []string{"node.role", "node.role=manager", "==manager", "node.role==@"} {
+		_, err := parseConstraint(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	node := *Node(
+		NodeID("nodeID1"),
+		Hostname("host1"),
+		NodeLabels(map[string]string{"zone": "east"}),
+	)
+
+	cases := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "node.id==nodeID1"},
+		{expr: "node.hostname!=host2"},
+		{expr: "node.role==WORKER"},
+		{expr: "node.platform.os==linux"},
+		{expr: "node.platform.arch==x86_64"},
+		{expr: "node.labels.zone==east"},
+		{expr: "node.labels.rack!=1"},
+		{expr: "engine.labels.engine==label"},
+		{expr: "node.role==manager", expected: `constraint node.role==manager: node.role is "worker"`},
+		{expr: "node.labels.zone!=east", expected: `constraint node.labels.zone!=east: node.labels.zone is "east"`},
+		{expr: "node.labels.rack==1", expected: "constraint node.labels.rack==1: node.labels.rack is not set"},
+		{expr: "node.color==blue", expected: "constraint node.color==blue: unknown key node.color"},
+	}
+	for _, c := range cases {
+		parsed, err := parseConstraint(c.expr)
+		require.NoError(t, err, c.expr)
+		assert.Equal(t, c.expected, parsed.check(node), c.expr)
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	drained := Node(NodeID("nodeID2"), Hostname("host2"))
+	drained.Spec.Availability = swarm.NodeAvailabilityDrain
+	nodes := []swarm.Node{
+		*Node(NodeID("nodeID1"), Hostname("host1")),
+		*drained,
+		*Node(NodeID("nodeID3"), Hostname("host3"), Manager()),
+	}
+	tasks := []swarm.Task{
+		{
+			NodeID:       "nodeID1",
+			DesiredState: swarm.TaskStateRunning,
+			Spec: swarm.TaskSpec{Resources: &swarm.ResourceRequirements{
+				Reservations: &swarm.Resources{MemoryBytes: 16 * 1024 * 1024},
+			}},
+		},
+	}
+
+	results, err := CheckPlacement(nodes, tasks, []string{"node.role==worker"}, &swarm.Resources{MemoryBytes: 8 * 1024 * 1024})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"insufficient memory: 8MiB requested, 4MiB available"}, results[0].Reasons)
+	assert.Equal(t, []string{"availability is drain"}, results[1].Reasons)
+	assert.Equal(t, []string{`constraint node.role==worker: node.role is "manager"`}, results[2].Reasons)
+
+	results, err = CheckPlacement(nodes, nil, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, results[0].Eligible())
+	assert.False(t, results[1].Eligible())
+	assert.True(t, results[2].Eligible())
+}
@@ -23,6 +23,7 @@ func NewServiceCommand(dockerCli *command.DockerCli) *cobra.Command {
 		newPsCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
+		newRestartCommand(dockerCli),
 		newScaleCommand(dockerCli),
 		newUpdateCommand(dockerCli),
 		newLogsCommand(dockerCli),
@@ -17,9 +17,17 @@ func newCreateCommand(dockerCli *command.DockerCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new service",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// The image can be omitted if it is set in the spec file
+			if opts.specFile != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.image = args[0]
+			if len(args) > 0 {
+				opts.image = args[0]
+			}
 			if len(args) > 1 {
 				opts.args = args[1:]
 			}
@@ -56,6 +64,7 @@ func newCreateCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.SetAnnotation(flagDNSSearch, "version", []string{"1.25"})
 	flags.Var(&opts.hosts, flagHost, "Set one or more custom host-to-IP mappings (host:ip)")
 	flags.SetAnnotation(flagHost, "version", []string{"1.25"})
+	flags.BoolVar(&opts.dryRun, flagDryRun, false, "Only report which nodes could run the tasks of the service")
 
 	flags.SetInterspersed(false)
 	return cmd
@@ -72,6 +81,24 @@ func runCreate(dockerCli *command.DockerCli, flags *pflag.FlagSet, opts *service
 		return err
 	}
 
+	if opts.specFile != "" {
+		spec, err := loadSpecFile(dockerCli.In(), opts.specFile)
+		if err != nil {
+			return err
+		}
+		mergeSpecFlags(flags, spec, service)
+		if opts.image != "" {
+			spec.TaskTemplate.ContainerSpec.Image = opts.image
+		}
+		if len(opts.args) > 0 {
+			spec.TaskTemplate.ContainerSpec.Args = opts.args
+		}
+		if err := validateSpecFile(spec); err != nil {
+			return err
+		}
+		service = *spec
+	}
+
 	specifiedSecrets := opts.secrets.Value()
 	if len(specifiedSecrets) > 0 {
 		// parse and validate secrets
@@ -92,6 +119,10 @@ func runCreate(dockerCli *command.DockerCli, flags *pflag.FlagSet, opts *service
 		service.TaskTemplate.ContainerSpec.Configs = configs
 	}
 
+	if opts.dryRun {
+		return runPlacementDryRun(ctx, dockerCli, service)
+	}
+
 	if err := resolveServiceImageDigest(dockerCli, &service); err != nil {
 		return err
 	}
@@ -99,7 +130,7 @@ func runCreate(dockerCli *command.DockerCli, flags *pflag.FlagSet, opts *service
 	// only send auth if flag was set
 	if opts.registryAuth {
 		// Retrieve encoded auth token from the image reference
-		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, opts.image)
+		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, service.TaskTemplate.ContainerSpec.Image)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/api/v2"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Values of the ImageStatus column of "docker service ls --check-image"
+const (
+	imageStatusCurrent  = "current"
+	imageStatusStale    = "stale"
+	imageStatusUnpinned = "unpinned"
+	imageStatusUnknown  = "unknown"
+)
+
+// manifestMediaTypes are the manifest types accepted when resolving a tag,
+// so that the registry returns the same digest the daemon pinned.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// digestResolver returns the digest a tag currently points to.
+type digestResolver func(ctx context.Context, ref reference.NamedTagged) (digest.Digest, error)
+
+// getImageStatuses compares the digest each service is pinned to with the
+// digest its tag currently resolves to, and returns the result by service ID.
+// Each image is only resolved once.
+func getImageStatuses(ctx context.Context, services []swarm.Service, resolve digestResolver) map[string]string {
+	var images []string
+	seen := make(map[string]bool)
+	for _, service := range services {
+		image := service.Spec.TaskTemplate.ContainerSpec.Image
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	results := make([]string, len(images))
+	var wg sync.WaitGroup
+	for i, image := range images {
+		wg.Add(1)
+		go func(i int, image string) {
+			defer wg.Done()
+			results[i] = checkImageStatus(ctx, image, resolve)
+		}(i, image)
+	}
+	wg.Wait()
+
+	byImage := make(map[string]string, len(images))
+	for i, image := range images {
+		byImage[image] = results[i]
+	}
+	statuses := make(map[string]string, len(services))
+	for _, service := range services {
+		statuses[service.ID] = byImage[service.Spec.TaskTemplate.ContainerSpec.Image]
+	}
+	return statuses
+}
+
+func checkImageStatus(ctx context.Context, image string, resolve digestResolver) string {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return imageStatusUnknown
+	}
+	canonical, ok := ref.(reference.Canonical)
+	if !ok {
+		return imageStatusUnpinned
+	}
+	nt, ok := ref.(reference.NamedTagged)
+	if !ok {
+		// pinned by digest only, so there is no tag to compare with
+		return imageStatusUnknown
+	}
+	tagged, err := reference.WithTag(reference.TrimNamed(nt), nt.Tag())
+	if err != nil {
+		return imageStatusUnknown
+	}
+
+	current, err := resolve(ctx, tagged)
+	if err != nil {
+		logrus.Debugf("failed to resolve %s: %v", reference.FamiliarString(tagged), err)
+		return imageStatusUnknown
+	}
+	if current != canonical.Digest() {
+		return imageStatusStale
+	}
+	return imageStatusCurrent
+}
+
+// registryDigestResolver resolves tags by querying the registry, using the
+// credentials stored for it.
+func registryDigestResolver(dockerCli command.Cli) digestResolver {
+	return func(ctx context.Context, ref reference.NamedTagged) (digest.Digest, error) {
+		repoInfo, err := registry.ParseRepositoryInfo(ref)
+		if err != nil {
+			return "", err
+		}
+		authConfig := command.ResolveAuthConfig(ctx, dockerCli, repoInfo.Index)
+
+		endpoints, err := registry.NewService(registry.ServiceOptions{}).LookupPullEndpoints(reference.Domain(repoInfo.Name))
+		if err != nil {
+			return "", err
+		}
+
+		lastErr := errors.Errorf("no registry endpoint found for %s", reference.FamiliarName(ref))
+		for _, endpoint := range endpoints {
+			if endpoint.Version != registry.APIVersion2 || endpoint.Mirror {
+				continue
+			}
+			dgst, err := fetchTagDigest(endpoint, repoInfo, authConfig, ref.Tag())
+			if err == nil {
+				return dgst, nil
+			}
+			lastErr = err
+		}
+		return "", lastErr
+	}
+}
+
+func fetchTagDigest(endpoint registry.APIEndpoint, repoInfo *registry.RepositoryInfo, authConfig types.AuthConfig, tag string) (digest.Digest, error) {
+	base := registry.NewTransport(endpoint.TLSConfig)
+	modifiers := registry.DockerHeaders(command.UserAgent(), http.Header{})
+	authTransport := transport.NewTransport(base, modifiers...)
+
+	challengeManager, _, err := registry.PingV2Registry(endpoint.URL, authTransport)
+	if err != nil {
+		return "", err
+	}
+
+	repoName := reference.Path(repoInfo.Name)
+	creds := registry.NewStaticCredentialStore(&authConfig)
+	tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+		Transport:   authTransport,
+		Credentials: creds,
+		Scopes: []auth.Scope{auth.RepositoryScope{
+			Repository: repoName,
+			Actions:    []string{"pull"},
+			Class:      repoInfo.Class,
+		}},
+		ClientID: registry.AuthClientID,
+	})
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler))
+	client := &http.Client{
+		Transport: transport.NewTransport(base, modifiers...),
+		Timeout:   30 * time.Second,
+	}
+
+	urlBuilder, err := v2.NewURLBuilderFromString(endpoint.URL.String(), false)
+	if err != nil {
+		return "", err
+	}
+	named, err := reference.WithName(repoName)
+	if err != nil {
+		return "", err
+	}
+	tagged, err := reference.WithTag(named, tag)
+	if err != nil {
+		return "", err
+	}
+	manifestURL, err := urlBuilder.BuildManifestURL(tagged)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("HEAD", manifestURL, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, mediaType := range manifestMediaTypes {
+		req.Header.Add("Accept", mediaType)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status fetching manifest for %s: %s", tag, resp.Status)
+	}
+	return digest.Parse(resp.Header.Get("Docker-Content-Digest"))
+}
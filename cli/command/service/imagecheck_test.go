@@ -0,0 +1,88 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+const (
+	testDigestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testDigestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func fakeDigestResolver(tags map[string]digest.Digest) digestResolver {
+	return func(ctx context.Context, ref reference.NamedTagged) (digest.Digest, error) {
+		dgst, ok := tags[reference.FamiliarString(ref)]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return dgst, nil
+	}
+}
+
+func TestCheckImageStatus(t *testing.T) {
+	resolve := fakeDigestResolver(map[string]digest.Digest{
+		"nginx:1.13": testDigestA,
+		"redis:4":    testDigestB,
+	})
+
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{image: "nginx:1.13@" + testDigestA, expected: imageStatusCurrent},
+		{image: "nginx:1.13@" + testDigestB, expected: imageStatusStale},
+		{image: "nginx:1.13", expected: imageStatusUnpinned},
+		{image: "nginx@" + testDigestA, expected: imageStatusUnknown},
+		{image: "mysql:5@" + testDigestA, expected: imageStatusUnknown},
+		{image: "INVALID", expected: imageStatusUnknown},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, checkImageStatus(context.Background(), c.image, resolve), c.image)
+	}
+}
+
+func TestGetImageStatuses(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		lookups = map[string]int{}
+	)
+	resolve := func(ctx context.Context, ref reference.NamedTagged) (digest.Digest, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups[reference.FamiliarString(ref)]++
+		return testDigestA, nil
+	}
+	newService := func(id, image string) swarm.Service {
+		return swarm.Service{
+			ID: id,
+			Spec: swarm.ServiceSpec{
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: swarm.ContainerSpec{Image: image}},
+			},
+		}
+	}
+	services := []swarm.Service{
+		newService("id1", "nginx:1.13@"+testDigestA),
+		newService("id2", "nginx:1.13@"+testDigestA),
+		newService("id3", "redis:4"),
+		newService("id4", "redis:4@"+testDigestB),
+		newService("id5", "nginx:1.13@"+testDigestA),
+	}
+
+	statuses := getImageStatuses(context.Background(), services, resolve)
+	assert.Equal(t, map[string]string{
+		"id1": imageStatusCurrent,
+		"id2": imageStatusCurrent,
+		"id3": imageStatusUnpinned,
+		"id4": imageStatusStale,
+		"id5": imageStatusCurrent,
+	}, statuses)
+	assert.Equal(t, map[string]int{"nginx:1.13": 1, "redis:4": 1}, lookups)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/idresolver"
 	"github.com/docker/docker/api/types"
 	apiclient "github.com/docker/docker/client"
 	"github.com/pkg/errors"
@@ -18,6 +19,9 @@ type inspectOptions struct {
 	refs   []string
 	format string
 	pretty bool
+
+	diff       bool
+	diffFormat string
 }
 
 func newInspectCommand(dockerCli *command.DockerCli) *cobra.Command {
@@ -33,6 +37,21 @@ func newInspectCommand(dockerCli *command.DockerCli) *cobra.Command {
 			if opts.pretty && len(opts.format) > 0 {
 				return errors.Errorf("--format is incompatible with human friendly format")
 			}
+			if cmd.Flags().Changed("diff-format") && !opts.diff {
+				return errors.Errorf("--diff-format can only be used with --diff")
+			}
+			if opts.diff {
+				if opts.pretty || len(opts.format) > 0 {
+					return errors.Errorf("--diff is incompatible with --format and --pretty")
+				}
+				if opts.diffFormat != diffFormatText && opts.diffFormat != diffFormatJSONPatch {
+					return errors.Errorf("invalid diff format %q: must be %q or %q", opts.diffFormat, diffFormatText, diffFormatJSONPatch)
+				}
+				if len(opts.refs) > 1 {
+					return errors.Errorf("--diff can only be used with a single service")
+				}
+				return runInspectDiff(dockerCli, opts)
+			}
 			return runInspect(dockerCli, opts)
 		},
 	}
@@ -40,6 +59,8 @@ func newInspectCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
 	flags.BoolVar(&opts.pretty, "pretty", false, "Print the information in a human friendly format")
+	flags.BoolVar(&opts.diff, "diff", false, "Show the changes from the previous spec")
+	flags.StringVar(&opts.diffFormat, "diff-format", diffFormatText, `Format of the changes shown by --diff ("text"|"json-patch")`)
 	return cmd
 }
 
@@ -60,12 +81,9 @@ func runInspect(dockerCli *command.DockerCli, opts inspectOptions) error {
 		return nil, nil, errors.Errorf("Error: no such service: %s", ref)
 	}
 
-	getNetwork := func(ref string) (interface{}, []byte, error) {
-		network, _, err := client.NetworkInspectWithRaw(ctx, ref, false)
-		if err == nil || !apiclient.IsErrNetworkNotFound(err) {
-			return network, nil, err
-		}
-		return nil, nil, errors.Errorf("Error: no such network: %s", ref)
+	resolver := idresolver.New(client, false)
+	resolveName := func(t interface{}, id string) (string, error) {
+		return resolver.Resolve(ctx, t, id)
 	}
 
 	f := opts.format
@@ -87,8 +105,34 @@ func runInspect(dockerCli *command.DockerCli, opts inspectOptions) error {
 		Format: formatter.NewServiceFormat(f),
 	}
 
-	if err := formatter.ServiceInspectWrite(serviceCtx, opts.refs, getRef, getNetwork); err != nil {
+	if err := formatter.ServiceInspectWrite(serviceCtx, opts.refs, getRef, resolveName); err != nil {
 		return cli.StatusError{StatusCode: 1, Status: err.Error()}
 	}
 	return nil
 }
+
+func runInspectDiff(dockerCli *command.DockerCli, opts inspectOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	service, _, err := client.ServiceInspectWithRaw(ctx, opts.refs[0], types.ServiceInspectOptions{})
+	if err != nil {
+		if apiclient.IsErrServiceNotFound(err) {
+			return errors.Errorf("Error: no such service: %s", opts.refs[0])
+		}
+		return err
+	}
+	if service.PreviousSpec == nil {
+		return errors.Errorf("service %s has no previous spec", opts.refs[0])
+	}
+
+	changes, err := diffServiceSpecs(service.PreviousSpec, &service.Spec)
+	if err != nil {
+		return err
+	}
+
+	if opts.diffFormat == diffFormatJSONPatch {
+		return writeSpecDiffJSONPatch(dockerCli.Out(), changes)
+	}
+	return writeSpecDiffText(dockerCli.Out(), changes)
+}
@@ -3,14 +3,17 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func formatServiceInspect(t *testing.T, format formatter.Format, now time.Time) string {
@@ -43,6 +46,19 @@ func formatServiceInspect(t *testing.T, format formatter.Format, now time.Time)
 			TaskTemplate: swarm.TaskSpec{
 				ContainerSpec: swarm.ContainerSpec{
 					Image: "foo/bar@sha256:this_is_a_test",
+					Secrets: []*swarm.SecretReference{
+						{
+							SecretID: "secretID",
+							File:     &swarm.SecretReferenceFileTarget{Name: "password"},
+						},
+					},
+					Configs: []*swarm.ConfigReference{
+						{
+							ConfigID:   "configID",
+							ConfigName: "nginx.conf",
+							File:       &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf"},
+						},
+					},
 				},
 				Networks: []swarm.NetworkAttachmentConfig{
 					{
@@ -89,11 +105,18 @@ func formatServiceInspect(t *testing.T, format formatter.Format, now time.Time)
 		func(ref string) (interface{}, []byte, error) {
 			return s, nil, nil
 		},
-		func(ref string) (interface{}, []byte, error) {
-			return types.NetworkResource{
-				ID:   "5vpyomhb6ievnk0i0o60gcnei",
-				Name: "mynetwork",
-			}, nil, nil
+		func(t interface{}, id string) (string, error) {
+			switch t.(type) {
+			case types.NetworkResource:
+				if id == "5vpyomhb6ievnk0i0o60gcnei" {
+					return "mynetwork", nil
+				}
+			case swarm.Secret:
+				if id == "secretID" {
+					return "db-password", nil
+				}
+			}
+			return id, nil
 		},
 	)
 	if err != nil {
@@ -112,6 +135,18 @@ func TestPrettyPrintWithNoUpdateConfig(t *testing.T) {
 	}
 }
 
+func TestPrettyPrintSecretsAndConfigs(t *testing.T) {
+	s := formatServiceInspect(t, formatter.NewServiceFormat("pretty"), time.Now())
+	assert.Contains(t, s, `
+Secrets:
+  Name = db-password
+   Target = password
+Configs:
+  Name = nginx.conf
+   Target = /etc/nginx/nginx.conf
+`)
+}
+
 func TestJSONFormatWithNoUpdateConfig(t *testing.T) {
 	now := time.Now()
 	// s1: [{"ID":..}]
@@ -138,3 +173,109 @@ func TestJSONFormatWithNoUpdateConfig(t *testing.T) {
 	t.Logf("m2=%+v", m2)
 	assert.Equal(t, m1, m2)
 }
+
+func TestPrettyPrintCompleteSpec(t *testing.T) {
+	delay := 5 * time.Second
+	attempts := uint64(3)
+	gracePeriod := 20 * time.Second
+	s := swarm.Service{
+		ID: "de179gar9d0o7ltdybungplod",
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "my_service"},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: swarm.ContainerSpec{
+					Image:           "foo/bar:latest",
+					Labels:          map[string]string{"com.example": "web"},
+					Command:         []string{"nginx"},
+					Hostname:        "web-host",
+					Groups:          []string{"staff"},
+					StopSignal:      "SIGQUIT",
+					StopGracePeriod: &gracePeriod,
+					ReadOnly:        true,
+					Privileges: &swarm.Privileges{
+						SELinuxContext: &swarm.SELinuxContext{User: "system_u", Type: "svirt_t"},
+					},
+					Healthcheck: &container.HealthConfig{
+						Test:     []string{"CMD-SHELL", "curl -f http://localhost"},
+						Interval: 30 * time.Second,
+						Retries:  3,
+					},
+					Hosts: []string{"10.0.0.1 db"},
+					DNSConfig: &swarm.DNSConfig{
+						Nameservers: []string{"8.8.8.8"},
+						Search:      []string{"example.com"},
+					},
+				},
+				RestartPolicy: &swarm.RestartPolicy{
+					Condition:   swarm.RestartPolicyConditionOnFailure,
+					Delay:       &delay,
+					MaxAttempts: &attempts,
+				},
+				LogDriver: &swarm.Driver{
+					Name:    "json-file",
+					Options: map[string]string{"max-size": "10m"},
+				},
+			},
+		},
+	}
+
+	b := new(bytes.Buffer)
+	err := formatter.ServiceInspectWrite(formatter.Context{Output: b, Format: formatter.NewServiceFormat("pretty")},
+		[]string{s.ID},
+		func(ref string) (interface{}, []byte, error) {
+			return s, nil, nil
+		},
+		func(t interface{}, id string) (string, error) {
+			return id, nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, `
+ID:		de179gar9d0o7ltdybungplod
+Name:		my_service
+Service Mode:
+RestartPolicy:
+ Condition:	on-failure
+ Delay:		5s
+ Max attempts:	3
+ContainerSpec:
+ Image:		foo/bar:latest
+ Labels:
+  com.example=web
+ Command:	nginx 
+ Hostname:	web-host
+ Groups:	staff 
+ Stop signal:	SIGQUIT
+ Stop grace period: 20s
+ Read only:	true
+Privileges:
+ SELinux context:	user=system_u type=svirt_t
+Healthcheck:
+ Test:		CMD-SHELL curl -f http://localhost
+ Interval:	30s
+ Retries:	3
+Log Driver:
+ Name:		json-file
+ Options:
+  max-size=10m
+Hosts:
+ 10.0.0.1 db
+DNS:
+ Nameservers:	8.8.8.8 
+ Search:	example.com 
+`, b.String())
+}
+
+func TestInspectDiffFormatFlag(t *testing.T) {
+	cmd := newInspectCommand(nil)
+	require.NoError(t, cmd.ParseFlags([]string{"--diff", "--diff-format", "json-patch", "web"}))
+	assert.Equal(t, []string{"web"}, cmd.Flags().Args())
+	diffFormat, err := cmd.Flags().GetString("diff-format")
+	require.NoError(t, err)
+	assert.Equal(t, diffFormatJSONPatch, diffFormat)
+
+	cmd = newInspectCommand(nil)
+	cmd.SetArgs([]string{"--diff-format", "json-patch", "web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.EqualError(t, cmd.Execute(), "--diff-format can only be used with --diff")
+}
@@ -15,9 +15,11 @@ import (
 )
 
 type listOptions struct {
-	quiet  bool
-	format string
-	filter opts.FilterOpt
+	quiet        bool
+	format       string
+	filter       opts.FilterOpt
+	updateStatus bool
+	checkImage   bool
 }
 
 func newListCommand(dockerCli *command.DockerCli) *cobra.Command {
@@ -37,6 +39,8 @@ func newListCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print services using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.updateStatus, "update-status", false, "Show the state of the last update of each service")
+	flags.BoolVar(&options.checkImage, "check-image", false, "Check whether the image of each service is up to date with the registry")
 
 	return cmd
 }
@@ -70,13 +74,29 @@ func runList(dockerCli *command.DockerCli, options listOptions) error {
 		}
 
 		info = GetServicesStatus(services, nodes, tasks)
+
+		if options.checkImage {
+			imageStatuses := getImageStatuses(ctx, services, registryDigestResolver(dockerCli))
+			for id, status := range imageStatuses {
+				serviceInfo := info[id]
+				serviceInfo.ImageStatus = status
+				info[id] = serviceInfo
+			}
+		}
 	}
 
 	format := options.format
 	if len(format) == 0 {
-		if len(dockerCli.ConfigFile().ServicesFormat) > 0 && !options.quiet {
+		switch {
+		case options.quiet:
+			format = formatter.TableFormatKey
+		case options.updateStatus || options.checkImage:
+			// the extra columns are added to the default table, which
+			// takes precedence over the format from the config file
+			format = string(formatter.NewServiceListTableFormat(options.updateStatus, options.checkImage))
+		case len(dockerCli.ConfigFile().ServicesFormat) > 0:
 			format = dockerCli.ConfigFile().ServicesFormat
-		} else {
+		default:
 			format = formatter.TableFormatKey
 		}
 	}
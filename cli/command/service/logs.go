@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -15,20 +18,46 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonlog"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/templates"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// LogsOptions holds the options for fetching the logs of services and tasks.
+// They are shared by "docker service logs" and "docker stack logs".
+type LogsOptions struct {
+	NoResolve  bool
+	NoTrunc    bool
+	NoTaskIDs  bool
+	Follow     bool
+	Since      string
+	Until      string
+	Timestamps bool
+	Tail       string
+	Format     string
+}
+
+// AddLogsFlags adds the flags for LogsOptions to flags.
+func AddLogsFlags(flags *pflag.FlagSet, opts *LogsOptions) {
+	// options specific to service logs
+	flags.BoolVar(&opts.NoResolve, "no-resolve", false, "Do not map IDs to Names in output")
+	flags.BoolVar(&opts.NoTrunc, "no-trunc", false, "Do not truncate output")
+	flags.BoolVar(&opts.NoTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
+	// options identical to container logs
+	flags.BoolVarP(&opts.Follow, "follow", "f", false, "Follow log output")
+	flags.StringVar(&opts.Since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.StringVar(&opts.Until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show timestamps")
+	flags.StringVar(&opts.Tail, "tail", "all", "Number of lines to show from the end of the logs")
+	flags.StringVar(&opts.Format, "format", "", `Format the output using the given Go template, or "json" for one JSON object per line`)
+}
+
 type logsOptions struct {
-	noResolve  bool
-	noTrunc    bool
-	noTaskIDs  bool
-	follow     bool
-	since      string
-	timestamps bool
-	tail       string
+	LogsOptions
 
 	target string
 }
@@ -52,102 +81,181 @@ func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 		Tags: map[string]string{"version": "1.29"},
 	}
 
-	flags := cmd.Flags()
-	// options specific to service logs
-	flags.BoolVar(&opts.noResolve, "no-resolve", false, "Do not map IDs to Names in output")
-	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Do not truncate output")
-	flags.BoolVar(&opts.noTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
-	// options identical to container logs
-	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
-	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
-	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
-	flags.StringVar(&opts.tail, "tail", "all", "Number of lines to show from the end of the logs")
+	AddLogsFlags(cmd.Flags(), &opts.LogsOptions)
 	return cmd
 }
 
-func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
-	ctx := context.Background()
+// logEntry is a single line of task output, as passed to the --format
+// template.
+type logEntry struct {
+	Timestamp time.Time
+	Service   string
+	ServiceID string
+	TaskID    string
+	Slot      int
+	Node      string
+	NodeID    string
+	Stream    string
+	Message   string
+}
 
-	options := types.ContainerLogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Since:      opts.since,
-		Timestamps: opts.timestamps,
-		Follow:     opts.follow,
-		Tail:       opts.tail,
-		Details:    true,
+func makeLogTemplate(format string) (*template.Template, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "json":
+		format = "{{json .}}"
 	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	// execute the template for an empty entry, so as to validate a bad
+	// template like "{{.badField}}"
+	return tmpl, tmpl.Execute(ioutil.Discard, &logEntry{})
+}
 
+func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
+	ctx := context.Background()
 	cli := dockerCli.Client()
 
-	var (
-		maxLength    = 1
-		responseBody io.ReadCloser
-		tty          bool
-	)
-
 	service, _, err := cli.ServiceInspectWithRaw(ctx, opts.target, types.ServiceInspectOptions{})
+	if err == nil {
+		return WriteServiceLogs(ctx, cli, service, &opts.LogsOptions, dockerCli.Out(), dockerCli.Err())
+	}
+	// if it's any error other than service not found, it's Real
+	if !client.IsErrServiceNotFound(err) {
+		return err
+	}
+
+	task, _, err := cli.TaskInspectWithRaw(ctx, opts.target)
 	if err != nil {
-		// if it's any error other than service not found, it's Real
-		if !client.IsErrServiceNotFound(err) {
-			return err
-		}
-		task, _, err := cli.TaskInspectWithRaw(ctx, opts.target)
-		if err != nil {
-			if client.IsErrTaskNotFound(err) {
-				// if the task isn't found, rewrite the error to be clear
-				// that we looked for services AND tasks and found none
-				err = fmt.Errorf("no such task or service")
-			}
-			return err
-		}
-		tty = task.Spec.ContainerSpec.TTY
-		// TODO(dperny) hot fix until we get a nice details system squared away,
-		// ignores details (including task context) if we have a TTY log
-		// if we don't do this, we'll vomit the huge context verbatim into the
-		// TTY log lines and that's Undesirable.
-		if tty {
-			options.Details = false
+		if client.IsErrTaskNotFound(err) {
+			// if the task isn't found, rewrite the error to be clear
+			// that we looked for services AND tasks and found none
+			err = fmt.Errorf("no such task or service")
 		}
+		return err
+	}
 
-		responseBody, err = cli.TaskLogs(ctx, opts.target, options)
-		if err != nil {
-			return err
-		}
+	fetch := func(ctx context.Context, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+		return cli.TaskLogs(ctx, task.ID, options)
+	}
+	tty := task.Spec.ContainerSpec.TTY
+	return writeLogs(ctx, cli, &opts.LogsOptions, fetch, tty, getMaxLength(task.Slot), dockerCli.Out(), dockerCli.Err())
+}
 
-		maxLength = getMaxLength(task.Slot)
-	} else {
-		tty = service.Spec.TaskTemplate.ContainerSpec.TTY
-		// TODO(dperny) hot fix until we get a nice details system squared away,
-		// ignores details (including task context) if we have a TTY log
-		if tty {
-			options.Details = false
-		}
+// WriteServiceLogs writes the logs of service to stdout and stderr, with each
+// line prefixed by the task that produced it, as done by "docker service
+// logs". It returns when the log stream ends.
+func WriteServiceLogs(ctx context.Context, apiClient client.APIClient, service swarm.Service, opts *LogsOptions, stdout, stderr io.Writer) error {
+	maxLength := 1
+	if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+		// if replicas are initialized, figure out if we need to pad them
+		replicas := *service.Spec.Mode.Replicated.Replicas
+		maxLength = getMaxLength(int(replicas))
+	}
 
-		responseBody, err = cli.ServiceLogs(ctx, opts.target, options)
-		if err != nil {
-			return err
+	fetch := func(ctx context.Context, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+		return apiClient.ServiceLogs(ctx, service.ID, options)
+	}
+	tty := service.Spec.TaskTemplate.ContainerSpec.TTY
+	return writeLogs(ctx, apiClient, opts, fetch, tty, maxLength, stdout, stderr)
+}
+
+type fetchLogsFunc func(ctx context.Context, options types.ContainerLogsOptions) (io.ReadCloser, error)
+
+func writeLogs(ctx context.Context, apiClient client.APIClient, opts *LogsOptions, fetch fetchLogsFunc, tty bool, maxLength int, stdout, stderr io.Writer) error {
+	tmpl, err := makeLogTemplate(opts.Format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "Error parsing format: " + err.Error()}
+	}
+
+	until, err := command.ParseUntil(opts.Until)
+	if err != nil {
+		return err
+	}
+
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.Since,
+		// The daemon does not filter logs by end time, so timestamps
+		// are needed to drop the lines after --until.
+		Timestamps: opts.Timestamps || !until.IsZero() || tmpl != nil,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
+		Details:    true,
+	}
+
+	// TODO(dperny) hot fix until we get a nice details system squared away,
+	// ignores details (including task context) if we have a TTY log
+	// if we don't do this, we'll vomit the huge context verbatim into the
+	// TTY log lines and that's Undesirable.
+	if tty {
+		if options.Timestamps && !opts.Timestamps {
+			return errors.New("--until and --format are not supported for logs with a TTY")
 		}
-		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
-			// if replicas are initialized, figure out if we need to pad them
-			replicas := *service.Spec.Mode.Replicated.Replicas
-			maxLength = getMaxLength(int(replicas))
+		options.Details = false
+	}
+
+	if !until.IsZero() && opts.Follow {
+		if until.After(time.Now()) {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, until)
+			defer cancel()
+		} else {
+			options.Follow = false
 		}
 	}
+
+	responseBody, err := fetch(ctx, options)
+	if err != nil {
+		return err
+	}
 	defer responseBody.Close()
 
 	if tty {
-		_, err = io.Copy(dockerCli.Out(), responseBody)
+		_, err = io.Copy(stdout, responseBody)
 		return err
 	}
 
-	taskFormatter := newTaskFormatter(cli, opts, maxLength)
-
-	stdout := &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: dockerCli.Out()}
-	stderr := &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: dockerCli.Err()}
+	taskFormatter := newTaskFormatter(apiClient, opts, maxLength)
+
+	stdoutWriter := &logWriter{
+		ctx:        ctx,
+		opts:       opts,
+		f:          taskFormatter,
+		w:          stdout,
+		stream:     "stdout",
+		timestamps: options.Timestamps,
+		until:      until,
+		tmpl:       tmpl,
+	}
+	stderrWriter := &logWriter{
+		ctx:        ctx,
+		opts:       opts,
+		f:          taskFormatter,
+		w:          stderr,
+		stream:     "stderr",
+		timestamps: options.Timestamps,
+		until:      until,
+		tmpl:       tmpl,
+	}
+	if tmpl != nil {
+		// formatted entries carry their stream, so write them all to
+		// stdout to keep a single ordered stream
+		stderrWriter.w = stdout
+	}
 
 	// TODO(aluzzardi): Do an io.Copy for services with TTY enabled.
-	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+	_, err = stdcopy.StdCopy(stdoutWriter, stderrWriter, responseBody)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		// the stream was closed because --until was reached
+		return nil
+	}
 	return err
 }
 
@@ -158,73 +266,106 @@ func getMaxLength(i int) int {
 
 type taskFormatter struct {
 	client  client.APIClient
-	opts    *logsOptions
+	opts    *LogsOptions
 	padding int
 
-	r     *idresolver.IDResolver
-	cache map[logContext]string
+	r       *idresolver.IDResolver
+	cache   map[logContext]string
+	entries map[logContext]logEntry
 }
 
-func newTaskFormatter(client client.APIClient, opts *logsOptions, padding int) *taskFormatter {
+func newTaskFormatter(client client.APIClient, opts *LogsOptions, padding int) *taskFormatter {
 	return &taskFormatter{
 		client:  client,
 		opts:    opts,
 		padding: padding,
-		r:       idresolver.New(client, opts.noResolve),
+		r:       idresolver.New(client, opts.NoResolve),
 		cache:   make(map[logContext]string),
+		entries: make(map[logContext]logEntry),
 	}
 }
 
-func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string, error) {
-	if cached, ok := f.cache[logCtx]; ok {
+// entry returns a logEntry with the service, task and node of logCtx filled
+// in.
+func (f *taskFormatter) entry(ctx context.Context, logCtx logContext) (logEntry, error) {
+	if cached, ok := f.entries[logCtx]; ok {
 		return cached, nil
 	}
 
 	nodeName, err := f.r.Resolve(ctx, swarm.Node{}, logCtx.nodeID)
 	if err != nil {
-		return "", err
+		return logEntry{}, err
 	}
 
 	serviceName, err := f.r.Resolve(ctx, swarm.Service{}, logCtx.serviceID)
 	if err != nil {
-		return "", err
+		return logEntry{}, err
 	}
 
 	task, _, err := f.client.TaskInspectWithRaw(ctx, logCtx.taskID)
+	if err != nil {
+		return logEntry{}, err
+	}
+
+	entry := logEntry{
+		Service:   serviceName,
+		ServiceID: logCtx.serviceID,
+		TaskID:    task.ID,
+		Slot:      task.Slot,
+		Node:      nodeName,
+		NodeID:    logCtx.nodeID,
+	}
+	f.entries[logCtx] = entry
+	return entry, nil
+}
+
+func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string, error) {
+	if cached, ok := f.cache[logCtx]; ok {
+		return cached, nil
+	}
+
+	entry, err := f.entry(ctx, logCtx)
 	if err != nil {
 		return "", err
 	}
 
-	taskName := fmt.Sprintf("%s.%d", serviceName, task.Slot)
-	if !f.opts.noTaskIDs {
-		if f.opts.noTrunc {
-			taskName += fmt.Sprintf(".%s", task.ID)
+	taskName := fmt.Sprintf("%s.%d", entry.Service, entry.Slot)
+	if !f.opts.NoTaskIDs {
+		if f.opts.NoTrunc {
+			taskName += fmt.Sprintf(".%s", entry.TaskID)
 		} else {
-			taskName += fmt.Sprintf(".%s", stringid.TruncateID(task.ID))
+			taskName += fmt.Sprintf(".%s", stringid.TruncateID(entry.TaskID))
 		}
 	}
 
-	paddingCount := f.padding - getMaxLength(task.Slot)
+	paddingCount := f.padding - getMaxLength(entry.Slot)
 	padding := ""
 	if paddingCount > 0 {
 		padding = strings.Repeat(" ", paddingCount)
 	}
-	formatted := taskName + "@" + nodeName + padding
+	formatted := taskName + "@" + entry.Node + padding
 	f.cache[logCtx] = formatted
 	return formatted, nil
 }
 
 type logWriter struct {
-	ctx  context.Context
-	opts *logsOptions
-	f    *taskFormatter
-	w    io.Writer
+	ctx    context.Context
+	opts   *LogsOptions
+	f      *taskFormatter
+	w      io.Writer
+	stream string
+
+	// timestamps is set if the daemon prefixes each message with a
+	// timestamp, which may be the case even if --timestamps was not passed.
+	timestamps bool
+	until      time.Time
+	tmpl       *template.Template
 }
 
 func (lw *logWriter) Write(buf []byte) (int, error) {
 	contextIndex := 0
 	numParts := 2
-	if lw.opts.timestamps {
+	if lw.timestamps {
 		contextIndex++
 		numParts++
 	}
@@ -234,11 +375,33 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		return 0, errors.Errorf("invalid context in log message: %v", string(buf))
 	}
 
+	var timestamp time.Time
+	if lw.timestamps {
+		var err error
+		timestamp, err = time.Parse(jsonlog.RFC3339NanoFixed, string(parts[0]))
+		if err != nil {
+			return 0, errors.Errorf("invalid timestamp in log message: %v", string(buf))
+		}
+		if !lw.until.IsZero() && timestamp.After(lw.until) {
+			return len(buf), nil
+		}
+	}
+
 	logCtx, err := lw.parseContext(string(parts[contextIndex]))
 	if err != nil {
 		return 0, err
 	}
 
+	if lw.tmpl != nil {
+		return lw.writeEntry(buf, logCtx, timestamp, parts[numParts-1])
+	}
+
+	if lw.timestamps && !lw.opts.Timestamps {
+		// drop the timestamp that was only requested for --until
+		parts = parts[1:]
+		contextIndex--
+	}
+
 	output := []byte{}
 	for i, part := range parts {
 		// First part doesn't get space separation.
@@ -264,6 +427,28 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	return len(buf), nil
 }
 
+func (lw *logWriter) writeEntry(buf []byte, logCtx logContext, timestamp time.Time, message []byte) (int, error) {
+	entry, err := lw.f.entry(lw.ctx, logCtx)
+	if err != nil {
+		return 0, err
+	}
+	entry.Timestamp = timestamp
+	entry.Stream = lw.stream
+	entry.Message = string(bytes.TrimSuffix(message, []byte("\n")))
+
+	// render the entry before writing it, so that each line is written
+	// with a single call
+	var output bytes.Buffer
+	if err := lw.tmpl.Execute(&output, entry); err != nil {
+		return 0, err
+	}
+	output.WriteByte('\n')
+	if _, err := lw.w.Write(output.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(buf), nil
+}
+
 func (lw *logWriter) parseContext(input string) (logContext, error) {
 	context := make(map[string]string)
 
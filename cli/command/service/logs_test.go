@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type fakeLogsClient struct {
+	client.Client
+}
+
+func (cli *fakeLogsClient) NodeInspectWithRaw(ctx context.Context, nodeID string) (swarm.Node, []byte, error) {
+	return swarm.Node{Description: swarm.NodeDescription{Hostname: "node-1"}}, nil, nil
+}
+
+func (cli *fakeLogsClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	return swarm.Service{Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}}}, nil, nil
+}
+
+func (cli *fakeLogsClient) TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+	return swarm.Task{ID: taskID, Slot: 2}, nil, nil
+}
+
+const (
+	testLogContext = "com.docker.swarm.node.id=nodeid,com.docker.swarm.service.id=serviceid,com.docker.swarm.task.id=taskid0123456789"
+	testLogLine    = "2017-06-01T10:00:00.000000000Z " + testLogContext + " hello world\n"
+)
+
+func newTestLogWriter(opts *LogsOptions, out *bytes.Buffer) *logWriter {
+	return &logWriter{
+		ctx:        context.Background(),
+		opts:       opts,
+		f:          newTaskFormatter(&fakeLogsClient{}, opts, 1),
+		w:          out,
+		stream:     "stdout",
+		timestamps: true,
+	}
+}
+
+func TestLogWriterStripsTimestamps(t *testing.T) {
+	out := new(bytes.Buffer)
+	lw := newTestLogWriter(&LogsOptions{}, out)
+
+	n, err := lw.Write([]byte(testLogLine))
+	require.NoError(t, err)
+	assert.Equal(t, len(testLogLine), n)
+	assert.Equal(t, "web.2.taskid012345@node-1    | hello world\n", out.String())
+}
+
+func TestLogWriterKeepsTimestamps(t *testing.T) {
+	out := new(bytes.Buffer)
+	lw := newTestLogWriter(&LogsOptions{Timestamps: true}, out)
+
+	_, err := lw.Write([]byte(testLogLine))
+	require.NoError(t, err)
+	assert.Equal(t, "2017-06-01T10:00:00.000000000Z web.2.taskid012345@node-1    | hello world\n", out.String())
+}
+
+func TestLogWriterUntil(t *testing.T) {
+	out := new(bytes.Buffer)
+	lw := newTestLogWriter(&LogsOptions{}, out)
+	lw.until = time.Date(2017, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	n, err := lw.Write([]byte(testLogLine))
+	require.NoError(t, err)
+	assert.Equal(t, len(testLogLine), n)
+	assert.Equal(t, "", out.String())
+}
+
+func TestLogWriterJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	lw := newTestLogWriter(&LogsOptions{}, out)
+	tmpl, err := makeLogTemplate("json")
+	require.NoError(t, err)
+	lw.tmpl = tmpl
+
+	_, err = lw.Write([]byte(testLogLine))
+	require.NoError(t, err)
+	assert.Equal(t, `{"Timestamp":"2017-06-01T10:00:00Z","Service":"web","ServiceID":"serviceid","TaskID":"taskid0123456789","Slot":2,"Node":"node-1","NodeID":"nodeid","Stream":"stdout","Message":"hello world"}`+"\n", out.String())
+}
+
+func TestMakeLogTemplateInvalid(t *testing.T) {
+	_, err := makeLogTemplate("{{.NoSuchField}}")
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/container"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -106,3 +107,20 @@ func TestHealthCheckOptionsToHealthConfigConflict(t *testing.T) {
 	_, err := opt.toHealthConfig()
 	assert.EqualError(t, err, "--no-healthcheck conflicts with --health-* options")
 }
+
+func TestUpdateOptionsOrderOnly(t *testing.T) {
+	flags := pflag.NewFlagSet("create", pflag.ContinueOnError)
+	flags.String(flagUpdateOrder, "", "")
+	flags.String(flagRollbackOrder, "", "")
+	assert.NoError(t, flags.Parse([]string{"--update-order", "start-first", "--rollback-order", "start-first"}))
+
+	opt := updateOptions{order: "start-first"}
+	updateConfig := opt.updateConfig(flags)
+	if assert.NotNil(t, updateConfig) {
+		assert.Equal(t, "start-first", updateConfig.Order)
+	}
+	rollbackConfig := opt.rollbackConfig(flags)
+	if assert.NotNil(t, rollbackConfig) {
+		assert.Equal(t, "start-first", rollbackConfig.Order)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/node"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// runPlacementDryRun reports which nodes of the swarm the tasks of the
+// service could be scheduled on, without creating the service.
+func runPlacementDryRun(ctx context.Context, dockerCli command.Cli, service swarm.ServiceSpec) error {
+	client := dockerCli.Client()
+
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+
+	taskFilter := filters.NewArgs()
+	taskFilter.Add("desired-state", string(swarm.TaskStateRunning))
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return err
+	}
+
+	var constraints []string
+	if service.TaskTemplate.Placement != nil {
+		constraints = service.TaskTemplate.Placement.Constraints
+	}
+	var reservations *swarm.Resources
+	if service.TaskTemplate.Resources != nil {
+		reservations = service.TaskTemplate.Resources.Reservations
+	}
+
+	results, err := node.CheckPlacement(nodes, tasks, constraints, reservations)
+	if err != nil {
+		return err
+	}
+
+	eligible := 0
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tRESULT\tREASON")
+	for _, result := range results {
+		status := "eligible"
+		if result.Eligible() {
+			eligible++
+		} else {
+			status = "rejected"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Node.ID, result.Node.Description.Hostname, status, strings.Join(result.Reasons, "; "))
+	}
+	w.Flush()
+
+	if eligible == 0 {
+		return errors.New("no suitable node for the tasks of the service")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/cli/internal/test/builders"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type fakePlacementClient struct {
+	client.Client
+	nodes []swarm.Node
+}
+
+func (cli *fakePlacementClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+	return cli.nodes, nil
+}
+
+func (cli *fakePlacementClient) TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+	return nil, nil
+}
+
+func TestRunPlacementDryRun(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cli := test.NewFakeCli(&fakePlacementClient{nodes: []swarm.Node{
+		*Node(NodeID("nodeID1"), Hostname("host1"), NodeLabels(map[string]string{"zone": "east"})),
+		*Node(NodeID("nodeID2"), Hostname("host2")),
+	}}, buf)
+
+	spec := swarm.ServiceSpec{TaskTemplate: swarm.TaskSpec{
+		Placement: &swarm.Placement{Constraints: []string{"node.labels.zone==east"}},
+	}}
+	assert.NoError(t, runPlacementDryRun(context.Background(), cli, spec))
+	assert.Equal(t, `ID                  HOSTNAME            RESULT              REASON
+nodeID1             host1               eligible            
+nodeID2             host2               rejected            constraint node.labels.zone==east: node.labels.zone is not set
+`, buf.String())
+}
+
+func TestRunPlacementDryRunNoSuitableNode(t *testing.T) {
+	cli := test.NewFakeCli(&fakePlacementClient{nodes: []swarm.Node{
+		*Node(NodeID("nodeID1"), Hostname("host1")),
+	}}, new(bytes.Buffer))
+
+	spec := swarm.ServiceSpec{TaskTemplate: swarm.TaskSpec{
+		Placement: &swarm.Placement{Constraints: []string{"node.role==manager"}},
+	}}
+	assert.EqualError(t, runPlacementDryRun(context.Background(), cli, spec), "no suitable node for the tasks of the service")
+}
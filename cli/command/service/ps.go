@@ -24,6 +24,7 @@ type psOptions struct {
 	noTrunc   bool
 	format    string
 	filter    opts.FilterOpt
+	details   bool
 }
 
 func newPsCommand(dockerCli command.Cli) *cobra.Command {
@@ -44,11 +45,16 @@ func newPsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noResolve, "no-resolve", false, "Do not map IDs to Names")
 	flags.StringVar(&options.format, "format", "", "Pretty-print tasks using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.details, "details", false, "Show exit codes, timelines, slot history and full errors of tasks")
 
 	return cmd
 }
 
 func runPS(dockerCli command.Cli, options psOptions) error {
+	if options.details && options.format != "" {
+		return errors.New("--details and --format cannot be combined")
+	}
+
 	client := dockerCli.Client()
 	ctx := context.Background()
 
@@ -110,6 +116,9 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 	}
 
 	format := options.format
+	if options.details {
+		format = string(formatter.NewTaskDetailsFormat(options.quiet))
+	}
 	if len(format) == 0 {
 		if len(dockerCli.ConfigFile().TasksFormat) > 0 && !options.quiet {
 			format = dockerCli.ConfigFile().TasksFormat
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+)
+
+type restartOptions struct {
+	parallelism uint64
+	delay       time.Duration
+	quiet       bool
+}
+
+const restartDescription = `Restart the tasks of a service.
+
+The tasks are restarted in batches, following the update config of the
+service. --parallelism and --delay change the batch settings for this
+restart only: the update config of the service is put back once the
+restart is done, with a second update of the service. The previous spec
+used by "service update --rollback" and "service inspect --diff" is then
+the spec of the restart.`
+
+func newRestartCommand(dockerCli *command.DockerCli) *cobra.Command {
+	options := restartOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "restart [OPTIONS] SERVICE",
+		Short: "Restart the tasks of a service",
+		Long:  restartDescription,
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestart(dockerCli, cmd.Flags(), options, args[0])
+		},
+		Tags: map[string]string{"version": "1.29"},
+	}
+
+	flags := cmd.Flags()
+	flags.Uint64Var(&options.parallelism, "parallelism", 1, "Maximum number of tasks restarted simultaneously (0 to restart all at once)")
+	flags.DurationVar(&options.delay, "delay", 0, "Delay between restarts (ns|us|ms|s|m|h)")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+
+	return cmd
+}
+
+func runRestart(dockerCli *command.DockerCli, flags *pflag.FlagSet, options restartOptions, serviceID string) error {
+	ctx := context.Background()
+
+	wait := func(serviceID string) error {
+		return waitOnService(ctx, dockerCli, serviceID, &serviceOptions{quiet: options.quiet})
+	}
+	return restartService(ctx, dockerCli.Client(), dockerCli.Out(), dockerCli.Err(), flags, options, serviceID, wait)
+}
+
+// restartService forces the tasks of a service to be restarted, and waits
+// for the restart with wait. The update config of the service is restored
+// afterwards if the batch settings were changed for the restart.
+func restartService(ctx context.Context, apiClient client.ServiceAPIClient, out, errOut io.Writer, flags *pflag.FlagSet, options restartOptions, serviceID string, wait func(string) error) error {
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	storedUpdateConfig := service.Spec.UpdateConfig
+	spec := restartSpec(flags, service.Spec, options)
+
+	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, spec, types.ServiceUpdateOptions{
+		RegistryAuthFrom: types.RegistryAuthFromSpec,
+	})
+	if err != nil {
+		return err
+	}
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(errOut, warning)
+	}
+
+	fmt.Fprintf(out, "%s\n", serviceID)
+
+	waitErr := wait(service.ID)
+	if reflect.DeepEqual(spec.UpdateConfig, storedUpdateConfig) {
+		return waitErr
+	}
+
+	// put back the update config of the service, whether or not the
+	// restart succeeded, so later updates are not affected by this one
+	if err := restoreUpdateConfig(ctx, apiClient, errOut, service.ID, spec.UpdateConfig, storedUpdateConfig); err != nil {
+		if waitErr != nil {
+			return errors.Errorf("%v\nfailed to restore the update config: %v", waitErr, err)
+		}
+		return errors.Wrap(err, "failed to restore the update config")
+	}
+	return waitErr
+}
+
+// restartSpec returns a copy of spec which forces its tasks to be restarted,
+// using the batch settings given by flags for this update only.
+func restartSpec(flags *pflag.FlagSet, spec swarm.ServiceSpec, options restartOptions) swarm.ServiceSpec {
+	if flags.Changed("parallelism") || flags.Changed("delay") {
+		updateConfig := swarm.UpdateConfig{}
+		if spec.UpdateConfig != nil {
+			updateConfig = *spec.UpdateConfig
+		}
+		if flags.Changed("parallelism") {
+			updateConfig.Parallelism = options.parallelism
+		}
+		if flags.Changed("delay") {
+			updateConfig.Delay = options.delay
+		}
+		spec.UpdateConfig = &updateConfig
+	}
+	spec.TaskTemplate.ForceUpdate++
+	return spec
+}
+
+// restoreUpdateConfig sets the update config of a service back to
+// updateConfig, unless it was changed by someone else since the restart.
+func restoreUpdateConfig(ctx context.Context, apiClient client.ServiceAPIClient, errOut io.Writer, serviceID string, restartUpdateConfig, updateConfig *swarm.UpdateConfig) error {
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(service.Spec.UpdateConfig, restartUpdateConfig) {
+		fmt.Fprintln(errOut, "The update config of the service was changed during the restart, and is left as is.")
+		return nil
+	}
+	if service.UpdateStatus != nil && service.UpdateStatus.State == swarm.UpdateStateUpdating {
+		fmt.Fprintln(errOut, "The restart continues in the background, using the stored update config for the remaining tasks.")
+	}
+
+	service.Spec.UpdateConfig = updateConfig
+	_, err = apiClient.ServiceUpdate(ctx, service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{
+		RegistryAuthFrom: types.RegistryAuthFromSpec,
+	})
+	return err
+}
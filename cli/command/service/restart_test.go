@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type fakeRestartClient struct {
+	client.Client
+	service swarm.Service
+	updates []swarm.ServiceSpec
+}
+
+func (cli *fakeRestartClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	return cli.service, nil, nil
+}
+
+func (cli *fakeRestartClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+	cli.updates = append(cli.updates, spec)
+	cli.service.Version.Index++
+	cli.service.Spec = spec
+	return types.ServiceUpdateResponse{}, nil
+}
+
+func parseRestartFlags(t *testing.T, args ...string) (*pflag.FlagSet, restartOptions) {
+	cmd := newRestartCommand(nil)
+	require.NoError(t, cmd.ParseFlags(args))
+	options := restartOptions{}
+	options.parallelism, _ = cmd.Flags().GetUint64("parallelism")
+	options.delay, _ = cmd.Flags().GetDuration("delay")
+	return cmd.Flags(), options
+}
+
+func TestRestartSpec(t *testing.T) {
+	updateConfig := &swarm.UpdateConfig{
+		Parallelism:   5,
+		Delay:         time.Minute,
+		FailureAction: swarm.UpdateFailureActionRollback,
+		Order:         swarm.UpdateOrderStartFirst,
+	}
+	spec := swarm.ServiceSpec{
+		TaskTemplate: swarm.TaskSpec{ForceUpdate: 3},
+		UpdateConfig: updateConfig,
+	}
+
+	flags, options := parseRestartFlags(t, "--parallelism", "2")
+	restart := restartSpec(flags, spec, options)
+	assert.Equal(t, uint64(4), restart.TaskTemplate.ForceUpdate)
+	assert.Equal(t, &swarm.UpdateConfig{
+		Parallelism:   2,
+		Delay:         time.Minute,
+		FailureAction: swarm.UpdateFailureActionRollback,
+		Order:         swarm.UpdateOrderStartFirst,
+	}, restart.UpdateConfig)
+
+	// the stored spec is left untouched
+	assert.Equal(t, uint64(3), spec.TaskTemplate.ForceUpdate)
+	assert.Equal(t, uint64(5), updateConfig.Parallelism)
+
+	flags, options = parseRestartFlags(t)
+	restart = restartSpec(flags, spec, options)
+	assert.Equal(t, updateConfig, restart.UpdateConfig)
+}
+
+func TestRestartSpecNoUpdateConfig(t *testing.T) {
+	flags, options := parseRestartFlags(t, "--delay", "5s")
+	restart := restartSpec(flags, swarm.ServiceSpec{}, options)
+	assert.Equal(t, uint64(1), restart.TaskTemplate.ForceUpdate)
+	assert.Equal(t, &swarm.UpdateConfig{Delay: 5 * time.Second}, restart.UpdateConfig)
+}
+
+func TestRestartServiceRestoresUpdateConfig(t *testing.T) {
+	updateConfig := &swarm.UpdateConfig{Parallelism: 5, Delay: time.Minute}
+	apiClient := &fakeRestartClient{service: swarm.Service{
+		ID:   "id1",
+		Spec: swarm.ServiceSpec{UpdateConfig: updateConfig},
+	}}
+	flags, options := parseRestartFlags(t, "--parallelism", "2", "--delay", "5s")
+
+	var waited []string
+	wait := func(serviceID string) error {
+		waited = append(waited, serviceID)
+		return nil
+	}
+	out := new(bytes.Buffer)
+	require.NoError(t, restartService(context.Background(), apiClient, out, new(bytes.Buffer), flags, options, "web", wait))
+
+	assert.Equal(t, "web\n", out.String())
+	assert.Equal(t, []string{"id1"}, waited)
+	require.Len(t, apiClient.updates, 2)
+	assert.Equal(t, &swarm.UpdateConfig{Parallelism: 2, Delay: 5 * time.Second}, apiClient.updates[0].UpdateConfig)
+	assert.Equal(t, uint64(1), apiClient.updates[0].TaskTemplate.ForceUpdate)
+
+	// the stored update config is unchanged after the restart
+	assert.Equal(t, &swarm.UpdateConfig{Parallelism: 5, Delay: time.Minute}, apiClient.service.Spec.UpdateConfig)
+	assert.Equal(t, uint64(1), apiClient.service.Spec.TaskTemplate.ForceUpdate)
+}
+
+func TestRestartServiceWithoutBatchSettings(t *testing.T) {
+	updateConfig := &swarm.UpdateConfig{Parallelism: 5}
+	apiClient := &fakeRestartClient{service: swarm.Service{
+		ID:   "id1",
+		Spec: swarm.ServiceSpec{UpdateConfig: updateConfig},
+	}}
+	flags, options := parseRestartFlags(t)
+
+	wait := func(string) error { return nil }
+	require.NoError(t, restartService(context.Background(), apiClient, new(bytes.Buffer), new(bytes.Buffer), flags, options, "web", wait))
+
+	require.Len(t, apiClient.updates, 1)
+	assert.Equal(t, updateConfig, apiClient.service.Spec.UpdateConfig)
+}
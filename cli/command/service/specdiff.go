@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	diffFormatText      = "text"
+	diffFormatJSONPatch = "json-patch"
+)
+
+// specChange is a single field-level difference between two service specs.
+// Path holds the JSON field names leading to the field; Old is unset when
+// the field was added and New is unset when it was removed.
+type specChange struct {
+	Path   []string
+	Old    interface{}
+	New    interface{}
+	HasOld bool
+	HasNew bool
+}
+
+// diffServiceSpecs returns the field-level differences between two service
+// specs, sorted by path. Fields are compared through their JSON encoding so
+// the paths match the output of "docker service inspect".
+func diffServiceSpecs(previous, current *swarm.ServiceSpec) ([]specChange, error) {
+	prevValue, err := specToValue(previous)
+	if err != nil {
+		return nil, err
+	}
+	currValue, err := specToValue(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []specChange
+	diffValues(nil, prevValue, currValue, &changes)
+	return changes, nil
+}
+
+func specToValue(spec *swarm.ServiceSpec) (interface{}, error) {
+	if spec == nil {
+		return map[string]interface{}{}, nil
+	}
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	// keep durations and byte sizes as exact integers
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func diffValues(path []string, previous, current interface{}, changes *[]specChange) {
+	prevMap, prevIsMap := previous.(map[string]interface{})
+	currMap, currIsMap := current.(map[string]interface{})
+	if !prevIsMap || !currIsMap {
+		if !reflect.DeepEqual(previous, current) {
+			*changes = append(*changes, specChange{Path: path, Old: previous, New: current, HasOld: true, HasNew: true})
+		}
+		return
+	}
+
+	keys := make(map[string]struct{}, len(prevMap)+len(currMap))
+	for key := range prevMap {
+		keys[key] = struct{}{}
+	}
+	for key := range currMap {
+		keys[key] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		fieldPath := append(append([]string{}, path...), key)
+		prevField, inPrev := prevMap[key]
+		currField, inCurr := currMap[key]
+		switch {
+		case !inPrev:
+			*changes = append(*changes, specChange{Path: fieldPath, New: currField, HasNew: true})
+		case !inCurr:
+			*changes = append(*changes, specChange{Path: fieldPath, Old: prevField, HasOld: true})
+		default:
+			diffValues(fieldPath, prevField, currField, changes)
+		}
+	}
+}
+
+// writeSpecDiffText prints the changes in a human readable form. Lists are
+// compared element by element, so that for example a single changed
+// environment variable is shown instead of the whole environment.
+func writeSpecDiffText(out io.Writer, changes []specChange) error {
+	for _, change := range changes {
+		fmt.Fprintf(out, "%s:\n", strings.Join(change.Path, "."))
+
+		prevList, prevIsList := change.Old.([]interface{})
+		currList, currIsList := change.New.([]interface{})
+		if !change.HasOld {
+			prevIsList = currIsList
+		}
+		if !change.HasNew {
+			currIsList = prevIsList
+		}
+		if prevIsList && currIsList {
+			removed, added := diffLists(prevList, currList)
+			if len(removed) == 0 && len(added) == 0 {
+				fmt.Fprintln(out, "  ~ order changed")
+			}
+			for _, value := range removed {
+				fmt.Fprintf(out, "  - %s\n", formatDiffValue(value))
+			}
+			for _, value := range added {
+				fmt.Fprintf(out, "  + %s\n", formatDiffValue(value))
+			}
+			continue
+		}
+
+		if change.HasOld {
+			fmt.Fprintf(out, "  - %s\n", formatDiffValue(change.Old))
+		}
+		if change.HasNew {
+			fmt.Fprintf(out, "  + %s\n", formatDiffValue(change.New))
+		}
+	}
+	return nil
+}
+
+// diffLists returns the elements only found in previous and the elements
+// only found in current.
+func diffLists(previous, current []interface{}) (removed, added []interface{}) {
+	counts := make(map[string]int)
+	for _, value := range current {
+		counts[formatDiffValue(value)]++
+	}
+	for _, value := range previous {
+		key := formatDiffValue(value)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		removed = append(removed, value)
+	}
+
+	counts = make(map[string]int)
+	for _, value := range previous {
+		counts[formatDiffValue(value)]++
+	}
+	for _, value := range current {
+		key := formatDiffValue(value)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		added = append(added, value)
+	}
+	return removed, added
+}
+
+func formatDiffValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
+
+// jsonPatchOperation is an operation of a JSON patch, as defined in RFC 6902.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON leaves out the value of remove operations only, as add and
+// replace operations must have one even when it is empty.
+func (o jsonPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+	type operation jsonPatchOperation
+	return json.Marshal(operation(o))
+}
+
+// writeSpecDiffJSONPatch prints the changes as a JSON patch which turns the
+// previous spec into the current one. Lists are replaced as a whole.
+func writeSpecDiffJSONPatch(out io.Writer, changes []specChange) error {
+	operations := []jsonPatchOperation{}
+	for _, change := range changes {
+		operation := jsonPatchOperation{Path: jsonPointer(change.Path), Value: change.New}
+		switch {
+		case !change.HasOld:
+			operation.Op = "add"
+		case !change.HasNew:
+			operation.Op = "remove"
+		default:
+			operation.Op = "replace"
+		}
+		operations = append(operations, operation)
+	}
+
+	raw, err := json.MarshalIndent(operations, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", raw)
+	return err
+}
+
+func jsonPointer(path []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var pointer bytes.Buffer
+	for _, token := range path {
+		pointer.WriteString("/")
+		pointer.WriteString(escaper.Replace(token))
+	}
+	return pointer.String()
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffTestSpecs() (*swarm.ServiceSpec, *swarm.ServiceSpec) {
+	previous := &swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: swarm.ContainerSpec{
+				Image: "nginx:1.12",
+				Env:   []string{"A=1", "B=2"},
+				Mounts: []mount.Mount{
+					{Type: mount.TypeVolume, Source: "data", Target: "/data"},
+				},
+			},
+			Placement: &swarm.Placement{Constraints: []string{"node.role==worker"}},
+		},
+	}
+	current := &swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: swarm.ContainerSpec{
+				Image: "nginx:1.13",
+				Env:   []string{"A=1", "B=3"},
+				Mounts: []mount.Mount{
+					{Type: mount.TypeVolume, Source: "data", Target: "/data"},
+				},
+			},
+			Resources: &swarm.ResourceRequirements{
+				Limits: &swarm.Resources{MemoryBytes: 1024},
+			},
+		},
+	}
+	return previous, current
+}
+
+func TestDiffServiceSpecs(t *testing.T) {
+	previous, current := diffTestSpecs()
+
+	changes, err := diffServiceSpecs(previous, current)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, jsonPointer(change.Path))
+	}
+	assert.Equal(t, []string{
+		"/TaskTemplate/ContainerSpec/Env",
+		"/TaskTemplate/ContainerSpec/Image",
+		"/TaskTemplate/Placement",
+		"/TaskTemplate/Resources",
+	}, paths)
+}
+
+func TestDiffServiceSpecsNoChanges(t *testing.T) {
+	previous, _ := diffTestSpecs()
+
+	changes, err := diffServiceSpecs(previous, previous)
+	require.NoError(t, err)
+	assert.Len(t, changes, 0)
+}
+
+func TestWriteSpecDiffText(t *testing.T) {
+	previous, current := diffTestSpecs()
+	changes, err := diffServiceSpecs(previous, current)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, writeSpecDiffText(out, changes))
+	assert.Equal(t, `TaskTemplate.ContainerSpec.Env:
+  - B=2
+  + B=3
+TaskTemplate.ContainerSpec.Image:
+  - nginx:1.12
+  + nginx:1.13
+TaskTemplate.Placement:
+  - {"Constraints":["node.role==worker"]}
+TaskTemplate.Resources:
+  + {"Limits":{"MemoryBytes":1024}}
+`, out.String())
+}
+
+func TestWriteSpecDiffJSONPatch(t *testing.T) {
+	previous, current := diffTestSpecs()
+	changes, err := diffServiceSpecs(previous, current)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, writeSpecDiffJSONPatch(out, changes))
+	assert.Equal(t, `[
+    {
+        "op": "replace",
+        "path": "/TaskTemplate/ContainerSpec/Env",
+        "value": [
+            "A=1",
+            "B=3"
+        ]
+    },
+    {
+        "op": "replace",
+        "path": "/TaskTemplate/ContainerSpec/Image",
+        "value": "nginx:1.13"
+    },
+    {
+        "op": "remove",
+        "path": "/TaskTemplate/Placement"
+    },
+    {
+        "op": "add",
+        "path": "/TaskTemplate/Resources",
+        "value": {
+            "Limits": {
+                "MemoryBytes": 1024
+            }
+        }
+    }
+]
+`, out.String())
+}
+
+func TestWriteSpecDiffJSONPatchEmptyValues(t *testing.T) {
+	changes := []specChange{
+		{Path: []string{"Labels", "empty"}, New: "", HasNew: true},
+		{Path: []string{"TaskTemplate", "ContainerSpec", "ReadOnly"}, Old: true, New: false, HasOld: true, HasNew: true},
+		{Path: []string{"UpdateConfig", "Parallelism"}, Old: float64(2), New: float64(0), HasOld: true, HasNew: true},
+		{Path: []string{"Labels", "gone"}, Old: "", HasOld: true},
+	}
+
+	out := new(bytes.Buffer)
+	require.NoError(t, writeSpecDiffJSONPatch(out, changes))
+	var operations []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &operations))
+	assert.Equal(t, []map[string]interface{}{
+		{"op": "add", "path": "/Labels/empty", "value": ""},
+		{"op": "replace", "path": "/TaskTemplate/ContainerSpec/ReadOnly", "value": false},
+		{"op": "replace", "path": "/UpdateConfig/Parallelism", "value": float64(0)},
+		{"op": "remove", "path": "/Labels/gone"},
+	}, operations)
+}
+
+func TestJSONPointerEscaping(t *testing.T) {
+	assert.Equal(t, "/Labels/com.example~1team~0x", jsonPointer([]string{"Labels", "com.example/team~x"}))
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/swarmkit/api/defaults"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// loadSpecFile reads a service spec from a JSON or YAML file, using the same
+// field names as the output of "docker service inspect". A path of "-" reads
+// the spec from in.
+func loadSpecFile(in io.Reader, path string) (*swarm.ServiceSpec, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if path == "-" {
+		content, err = ioutil.ReadAll(in)
+	} else {
+		content, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read service spec")
+	}
+
+	spec, err := parseSpec(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse service spec %s", path)
+	}
+	return spec, nil
+}
+
+func parseSpec(content []byte) (*swarm.ServiceSpec, error) {
+	spec := &swarm.ServiceSpec{}
+
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("{")) {
+		if err := json.Unmarshal(content, spec); err != nil {
+			return nil, err
+		}
+		return spec, nil
+	}
+
+	// The spec types only carry JSON tags, so YAML is converted to JSON
+	// before being decoded.
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	if _, ok := raw.(map[interface{}]interface{}); !ok {
+		return nil, errors.New("top-level object must be a mapping")
+	}
+	converted, err := loader.ConvertToStringKeys(raw)
+	if err != nil {
+		return nil, err
+	}
+	content, err = json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// mergeSpecFlags copies the fields set by flags of "service create" from
+// flagSpec onto a spec loaded with --spec-file, so that the flags override
+// the values in the file.
+func mergeSpecFlags(flags *pflag.FlagSet, spec *swarm.ServiceSpec, flagSpec swarm.ServiceSpec) {
+	flags.Visit(func(flag *pflag.Flag) {
+		if override, ok := specFlagOverrides[flag.Name]; ok {
+			override(spec, &flagSpec)
+		}
+	})
+}
+
+type specOverride func(spec, flagSpec *swarm.ServiceSpec)
+
+// specFlagOverrides maps the flags of "service create" to the fields of the
+// service spec they set. Secrets and configs are resolved separately.
+var specFlagOverrides = map[string]specOverride{
+	flagName: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.Name = flagSpec.Name
+	},
+	flagLabel: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.Labels = flagSpec.Labels
+	},
+	flagMode: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.Mode = flagSpec.Mode
+	},
+	flagReplicas: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.Mode = flagSpec.Mode
+	},
+	flagContainerLabel: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Labels = flagSpec.TaskTemplate.ContainerSpec.Labels
+	},
+	flagEnv: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Env = flagSpec.TaskTemplate.ContainerSpec.Env
+	},
+	flagEnvFile: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Env = flagSpec.TaskTemplate.ContainerSpec.Env
+	},
+	flagEntrypoint: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Command = flagSpec.TaskTemplate.ContainerSpec.Command
+	},
+	flagHostname: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Hostname = flagSpec.TaskTemplate.ContainerSpec.Hostname
+	},
+	flagWorkdir: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Dir = flagSpec.TaskTemplate.ContainerSpec.Dir
+	},
+	flagUser: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.User = flagSpec.TaskTemplate.ContainerSpec.User
+	},
+	flagGroup: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Groups = flagSpec.TaskTemplate.ContainerSpec.Groups
+	},
+	flagCredentialSpec: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Privileges = flagSpec.TaskTemplate.ContainerSpec.Privileges
+	},
+	flagStopSignal: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.StopSignal = flagSpec.TaskTemplate.ContainerSpec.StopSignal
+	},
+	flagStopGracePeriod: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.StopGracePeriod = flagSpec.TaskTemplate.ContainerSpec.StopGracePeriod
+	},
+	flagTTY: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.TTY = flagSpec.TaskTemplate.ContainerSpec.TTY
+	},
+	flagReadOnly: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.ReadOnly = flagSpec.TaskTemplate.ContainerSpec.ReadOnly
+	},
+	flagMount: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Mounts = flagSpec.TaskTemplate.ContainerSpec.Mounts
+	},
+	flagDNS: func(spec, flagSpec *swarm.ServiceSpec) {
+		specDNSConfig(spec).Nameservers = flagSpec.TaskTemplate.ContainerSpec.DNSConfig.Nameservers
+	},
+	flagDNSOption: func(spec, flagSpec *swarm.ServiceSpec) {
+		specDNSConfig(spec).Options = flagSpec.TaskTemplate.ContainerSpec.DNSConfig.Options
+	},
+	flagDNSSearch: func(spec, flagSpec *swarm.ServiceSpec) {
+		specDNSConfig(spec).Search = flagSpec.TaskTemplate.ContainerSpec.DNSConfig.Search
+	},
+	flagHost: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.ContainerSpec.Hosts = flagSpec.TaskTemplate.ContainerSpec.Hosts
+	},
+	flagHealthCmd:         overrideHealthcheck,
+	flagHealthInterval:    overrideHealthcheck,
+	flagHealthTimeout:     overrideHealthcheck,
+	flagHealthRetries:     overrideHealthcheck,
+	flagHealthStartPeriod: overrideHealthcheck,
+	flagNoHealthcheck:     overrideHealthcheck,
+	flagLimitCPU: func(spec, flagSpec *swarm.ServiceSpec) {
+		specLimits(spec).NanoCPUs = flagSpec.TaskTemplate.Resources.Limits.NanoCPUs
+	},
+	flagLimitMemory: func(spec, flagSpec *swarm.ServiceSpec) {
+		specLimits(spec).MemoryBytes = flagSpec.TaskTemplate.Resources.Limits.MemoryBytes
+	},
+	flagReserveCPU: func(spec, flagSpec *swarm.ServiceSpec) {
+		specReservations(spec).NanoCPUs = flagSpec.TaskTemplate.Resources.Reservations.NanoCPUs
+	},
+	flagReserveMemory: func(spec, flagSpec *swarm.ServiceSpec) {
+		specReservations(spec).MemoryBytes = flagSpec.TaskTemplate.Resources.Reservations.MemoryBytes
+	},
+	flagRestartCondition: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRestartPolicy(spec).Condition = flagSpec.TaskTemplate.RestartPolicy.Condition
+	},
+	flagRestartDelay: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRestartPolicy(spec).Delay = flagSpec.TaskTemplate.RestartPolicy.Delay
+	},
+	flagRestartMaxAttempts: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRestartPolicy(spec).MaxAttempts = flagSpec.TaskTemplate.RestartPolicy.MaxAttempts
+	},
+	flagRestartWindow: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRestartPolicy(spec).Window = flagSpec.TaskTemplate.RestartPolicy.Window
+	},
+	flagConstraint: func(spec, flagSpec *swarm.ServiceSpec) {
+		specPlacement(spec).Constraints = flagSpec.TaskTemplate.Placement.Constraints
+	},
+	flagPlacementPref: func(spec, flagSpec *swarm.ServiceSpec) {
+		specPlacement(spec).Preferences = flagSpec.TaskTemplate.Placement.Preferences
+	},
+	flagNetwork: func(spec, flagSpec *swarm.ServiceSpec) {
+		spec.TaskTemplate.Networks = flagSpec.TaskTemplate.Networks
+	},
+	flagLogDriver: overrideLogDriver,
+	flagLogOpt:    overrideLogDriver,
+	flagUpdateParallelism: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).Parallelism = flagSpec.UpdateConfig.Parallelism
+	},
+	flagUpdateDelay: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).Delay = flagSpec.UpdateConfig.Delay
+	},
+	flagUpdateMonitor: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).Monitor = flagSpec.UpdateConfig.Monitor
+	},
+	flagUpdateFailureAction: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).FailureAction = flagSpec.UpdateConfig.FailureAction
+	},
+	flagUpdateMaxFailureRatio: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).MaxFailureRatio = flagSpec.UpdateConfig.MaxFailureRatio
+	},
+	flagUpdateOrder: func(spec, flagSpec *swarm.ServiceSpec) {
+		specUpdateConfig(spec).Order = flagSpec.UpdateConfig.Order
+	},
+	flagRollbackParallelism: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).Parallelism = flagSpec.RollbackConfig.Parallelism
+	},
+	flagRollbackDelay: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).Delay = flagSpec.RollbackConfig.Delay
+	},
+	flagRollbackMonitor: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).Monitor = flagSpec.RollbackConfig.Monitor
+	},
+	flagRollbackFailureAction: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).FailureAction = flagSpec.RollbackConfig.FailureAction
+	},
+	flagRollbackMaxFailureRatio: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).MaxFailureRatio = flagSpec.RollbackConfig.MaxFailureRatio
+	},
+	flagRollbackOrder: func(spec, flagSpec *swarm.ServiceSpec) {
+		specRollbackConfig(spec).Order = flagSpec.RollbackConfig.Order
+	},
+	flagEndpointMode: func(spec, flagSpec *swarm.ServiceSpec) {
+		specEndpoint(spec).Mode = flagSpec.EndpointSpec.Mode
+	},
+	flagPublish: func(spec, flagSpec *swarm.ServiceSpec) {
+		specEndpoint(spec).Ports = flagSpec.EndpointSpec.Ports
+	},
+}
+
+func overrideHealthcheck(spec, flagSpec *swarm.ServiceSpec) {
+	spec.TaskTemplate.ContainerSpec.Healthcheck = flagSpec.TaskTemplate.ContainerSpec.Healthcheck
+}
+
+func overrideLogDriver(spec, flagSpec *swarm.ServiceSpec) {
+	spec.TaskTemplate.LogDriver = flagSpec.TaskTemplate.LogDriver
+}
+
+func specDNSConfig(spec *swarm.ServiceSpec) *swarm.DNSConfig {
+	if spec.TaskTemplate.ContainerSpec.DNSConfig == nil {
+		spec.TaskTemplate.ContainerSpec.DNSConfig = &swarm.DNSConfig{}
+	}
+	return spec.TaskTemplate.ContainerSpec.DNSConfig
+}
+
+func specResources(spec *swarm.ServiceSpec) *swarm.ResourceRequirements {
+	if spec.TaskTemplate.Resources == nil {
+		spec.TaskTemplate.Resources = &swarm.ResourceRequirements{}
+	}
+	return spec.TaskTemplate.Resources
+}
+
+func specLimits(spec *swarm.ServiceSpec) *swarm.Resources {
+	resources := specResources(spec)
+	if resources.Limits == nil {
+		resources.Limits = &swarm.Resources{}
+	}
+	return resources.Limits
+}
+
+func specReservations(spec *swarm.ServiceSpec) *swarm.Resources {
+	resources := specResources(spec)
+	if resources.Reservations == nil {
+		resources.Reservations = &swarm.Resources{}
+	}
+	return resources.Reservations
+}
+
+func specRestartPolicy(spec *swarm.ServiceSpec) *swarm.RestartPolicy {
+	if spec.TaskTemplate.RestartPolicy == nil {
+		spec.TaskTemplate.RestartPolicy = defaultRestartPolicy()
+	}
+	return spec.TaskTemplate.RestartPolicy
+}
+
+func specPlacement(spec *swarm.ServiceSpec) *swarm.Placement {
+	if spec.TaskTemplate.Placement == nil {
+		spec.TaskTemplate.Placement = &swarm.Placement{}
+	}
+	return spec.TaskTemplate.Placement
+}
+
+func specUpdateConfig(spec *swarm.ServiceSpec) *swarm.UpdateConfig {
+	if spec.UpdateConfig == nil {
+		spec.UpdateConfig = updateConfigFromDefaults(defaults.Service.Update)
+	}
+	return spec.UpdateConfig
+}
+
+func specRollbackConfig(spec *swarm.ServiceSpec) *swarm.UpdateConfig {
+	if spec.RollbackConfig == nil {
+		spec.RollbackConfig = updateConfigFromDefaults(defaults.Service.Rollback)
+	}
+	return spec.RollbackConfig
+}
+
+func specEndpoint(spec *swarm.ServiceSpec) *swarm.EndpointSpec {
+	if spec.EndpointSpec == nil {
+		spec.EndpointSpec = &swarm.EndpointSpec{}
+	}
+	return spec.EndpointSpec
+}
+
+// validateSpecFile returns an error if a spec loaded with --spec-file cannot
+// be used to create a service.
+func validateSpecFile(spec *swarm.ServiceSpec) error {
+	if spec.TaskTemplate.ContainerSpec.Image == "" {
+		return errors.Errorf("no image specified: pass an IMAGE argument or set TaskTemplate.ContainerSpec.Image in the --%s", flagSpecFile)
+	}
+	return nil
+}
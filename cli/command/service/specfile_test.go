@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestParseSpecJSON(t *testing.T) {
+	spec, err := parseSpec([]byte(`{
+    "Name": "web",
+    "TaskTemplate": {
+        "ContainerSpec": {
+            "Image": "nginx:alpine",
+            "Env": ["FOO=bar"]
+        }
+    },
+    "Mode": {"Replicated": {"Replicas": 3}}
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, "nginx:alpine", spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, []string{"FOO=bar"}, spec.TaskTemplate.ContainerSpec.Env)
+	require.NotNil(t, spec.Mode.Replicated)
+	assert.Equal(t, uint64(3), *spec.Mode.Replicated.Replicas)
+}
+
+func TestParseSpecYAML(t *testing.T) {
+	spec, err := parseSpec([]byte(`
+Name: web
+Labels:
+  com.example.team: frontend
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx:alpine
+    StopGracePeriod: 10000000000
+  Placement:
+    Constraints:
+      - node.role==worker
+Mode:
+  Global: {}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, map[string]string{"com.example.team": "frontend"}, spec.Labels)
+	assert.Equal(t, "nginx:alpine", spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, 10*time.Second, *spec.TaskTemplate.ContainerSpec.StopGracePeriod)
+	assert.Equal(t, []string{"node.role==worker"}, spec.TaskTemplate.Placement.Constraints)
+	assert.NotNil(t, spec.Mode.Global)
+}
+
+func TestParseSpecInvalid(t *testing.T) {
+	_, err := parseSpec([]byte(`- foo`))
+	assert.EqualError(t, err, "top-level object must be a mapping")
+
+	_, err = parseSpec([]byte(`{"Name": 1}`))
+	assert.Error(t, err)
+}
+
+func TestMergeSpecFlags(t *testing.T) {
+	replicas := uint64(3)
+	spec := &swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: swarm.ContainerSpec{
+				Image: "nginx:alpine",
+				Env:   []string{"FOO=bar"},
+				User:  "nobody",
+			},
+			RestartPolicy: &swarm.RestartPolicy{
+				Condition: swarm.RestartPolicyConditionAny,
+			},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}
+
+	opts := newServiceOptions()
+	flags := pflag.NewFlagSet("create", pflag.ContinueOnError)
+	addServiceFlags(flags, opts, nil)
+	flags.StringVar(&opts.mode, flagMode, "replicated", "")
+	flags.Var(&opts.env, flagEnv, "")
+	require.NoError(t, flags.Parse([]string{"--env", "FOO=baz", "--replicas", "5", "--restart-delay", "5s"}))
+
+	flagSpec, err := opts.ToService(context.Background(), nil, flags)
+	require.NoError(t, err)
+
+	mergeSpecFlags(flags, spec, flagSpec)
+
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, "nginx:alpine", spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, "nobody", spec.TaskTemplate.ContainerSpec.User)
+	assert.Equal(t, []string{"FOO=baz"}, spec.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, uint64(5), *spec.Mode.Replicated.Replicas)
+	assert.Equal(t, swarm.RestartPolicyConditionAny, spec.TaskTemplate.RestartPolicy.Condition)
+	assert.Equal(t, 5*time.Second, *spec.TaskTemplate.RestartPolicy.Delay)
+}
+
+func TestMergeSpecFlagsOrderOnly(t *testing.T) {
+	spec := &swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Name: "web"},
+		UpdateConfig: &swarm.UpdateConfig{Parallelism: 2},
+	}
+
+	opts := newServiceOptions()
+	flags := pflag.NewFlagSet("update", pflag.ContinueOnError)
+	addServiceFlags(flags, opts, nil)
+	flags.StringVar(&opts.mode, flagMode, "replicated", "")
+	require.NoError(t, flags.Parse([]string{"--update-order", "start-first", "--rollback-order", "start-first"}))
+
+	flagSpec, err := opts.ToService(context.Background(), nil, flags)
+	require.NoError(t, err)
+
+	mergeSpecFlags(flags, spec, flagSpec)
+
+	assert.Equal(t, uint64(2), spec.UpdateConfig.Parallelism)
+	assert.Equal(t, swarm.UpdateOrderStartFirst, spec.UpdateConfig.Order)
+	assert.Equal(t, swarm.UpdateOrderStartFirst, spec.RollbackConfig.Order)
+}
+
+func TestValidateSpecFile(t *testing.T) {
+	assert.Error(t, validateSpecFile(&swarm.ServiceSpec{}))
+
+	spec := &swarm.ServiceSpec{}
+	spec.TaskTemplate.ContainerSpec.Image = "nginx:alpine"
+	assert.NoError(t, validateSpecFile(spec))
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
@@ -45,6 +46,9 @@ func newUpdateCommand(dockerCli *command.DockerCli) *cobra.Command {
 	addServiceFlags(flags, options, nil)
 
 	flags.Var(newListOptsVar(), flagEnvRemove, "Remove an environment variable")
+	flags.Var(newListOptsVar(), flagEnvFileAdd, "Add or update the environment variables of a file")
+	flags.Var(newListOptsVar(), flagEnvFileRemove, "Remove the environment variables of a file")
+	flags.Var(newListOptsVar(), flagEnvSync, "Replace the environment with the variables of a file")
 	flags.Var(newListOptsVar(), flagGroupRemove, "Remove a previously added supplementary user group from the container")
 	flags.SetAnnotation(flagGroupRemove, "version", []string{"1.25"})
 	flags.Var(newListOptsVar(), flagLabelRemove, "Remove a label by its key")
@@ -158,12 +162,12 @@ func runUpdate(dockerCli *command.DockerCli, flags *pflag.FlagSet, options *serv
 		updateOpts.Rollback = "previous"
 	}
 
-	err = updateService(ctx, apiClient, flags, spec)
+	spec, previousEnv, err := updateSpec(ctx, apiClient, dockerCli.In(), flags, options.specFile, spec)
 	if err != nil {
 		return err
 	}
 
-	if flags.Changed("image") {
+	if flags.Changed("image") || flags.Changed(flagSpecFile) {
 		if err := resolveServiceImageDigest(dockerCli, spec); err != nil {
 			return err
 		}
@@ -212,6 +216,10 @@ func runUpdate(dockerCli *command.DockerCli, flags *pflag.FlagSet, options *serv
 		fmt.Fprintln(dockerCli.Err(), warning)
 	}
 
+	if flags.Changed(flagEnvSync) {
+		printEnvironmentDiff(dockerCli.Err(), previousEnv, spec.TaskTemplate.ContainerSpec.Env)
+	}
+
 	fmt.Fprintf(dockerCli.Out(), "%s\n", serviceID)
 
 	if options.detach {
@@ -225,6 +233,29 @@ func runUpdate(dockerCli *command.DockerCli, flags *pflag.FlagSet, options *serv
 	return waitOnService(ctx, dockerCli, serviceID, options)
 }
 
+// updateSpec replaces spec with the spec file at specFile, if any, and applies
+// the flags to it. It also returns the environment of spec before any change,
+// for the diff of --env-sync.
+func updateSpec(ctx context.Context, apiClient client.NetworkAPIClient, in io.Reader, flags *pflag.FlagSet, specFile string, spec *swarm.ServiceSpec) (*swarm.ServiceSpec, []string, error) {
+	previousEnv := append([]string{}, spec.TaskTemplate.ContainerSpec.Env...)
+
+	if specFile != "" {
+		fileSpec, err := loadSpecFile(in, specFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fileSpec.Name == "" {
+			fileSpec.Name = spec.Name
+		}
+		spec = fileSpec
+	}
+
+	if err := updateService(ctx, apiClient, flags, spec); err != nil {
+		return nil, nil, err
+	}
+	return spec, previousEnv, nil
+}
+
 // nolint: gocyclo
 func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags *pflag.FlagSet, spec *swarm.ServiceSpec) error {
 	updateString := func(flag string, field *string) {
@@ -286,7 +317,9 @@ func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags
 	updateString("image", &cspec.Image)
 	updateStringToSlice(flags, "args", &cspec.Args)
 	updateStringToSlice(flags, flagEntrypoint, &cspec.Command)
-	updateEnvironment(flags, &cspec.Env)
+	if err := updateEnvironment(flags, &cspec.Env); err != nil {
+		return err
+	}
 	updateString(flagWorkdir, &cspec.Dir)
 	updateString(flagUser, &cspec.User)
 	updateString(flagHostname, &cspec.Hostname)
@@ -549,15 +582,33 @@ func updateLabels(flags *pflag.FlagSet, field *map[string]string) {
 	}
 }
 
-func updateEnvironment(flags *pflag.FlagSet, field *[]string) {
-	if flags.Changed(flagEnvAdd) {
+func updateEnvironment(flags *pflag.FlagSet, field *[]string) error {
+	if flags.Changed(flagEnvSync) {
+		if anyChanged(flags, flagEnvAdd, flagEnvRemove, flagEnvFileAdd, flagEnvFileRemove) {
+			return errors.Errorf("--%s cannot be combined with other flags changing the environment", flagEnvSync)
+		}
+		env, err := readEnvFiles(flags, flagEnvSync)
+		if err != nil {
+			return err
+		}
+		*field = env
+		return nil
+	}
+
+	if flags.Changed(flagEnvAdd) || flags.Changed(flagEnvFileAdd) {
+		toAdd, err := readEnvFiles(flags, flagEnvFileAdd)
+		if err != nil {
+			return err
+		}
+		if flags.Changed(flagEnvAdd) {
+			toAdd = append(toAdd, flags.Lookup(flagEnvAdd).Value.(*opts.ListOpts).GetAll()...)
+		}
+
 		envSet := map[string]string{}
 		for _, v := range *field {
 			envSet[envKey(v)] = v
 		}
-
-		value := flags.Lookup(flagEnvAdd).Value.(*opts.ListOpts)
-		for _, v := range value.GetAll() {
+		for _, v := range toAdd {
 			envSet[envKey(v)] = v
 		}
 
@@ -568,7 +619,63 @@ func updateEnvironment(flags *pflag.FlagSet, field *[]string) {
 	}
 
 	toRemove := buildToRemoveSet(flags, flagEnvRemove)
+	fileEnv, err := readEnvFiles(flags, flagEnvFileRemove)
+	if err != nil {
+		return err
+	}
+	for _, v := range fileEnv {
+		toRemove[envKey(v)] = struct{}{}
+	}
 	*field = removeItems(*field, toRemove, envKey)
+	return nil
+}
+
+// readEnvFiles returns the variables of the env files given to flag
+func readEnvFiles(flags *pflag.FlagSet, flag string) ([]string, error) {
+	if !flags.Changed(flag) {
+		return nil, nil
+	}
+	files := flags.Lookup(flag).Value.(*opts.ListOpts).GetAll()
+	return runconfigopts.ReadKVStrings(files, nil)
+}
+
+// printEnvironmentDiff prints the keys of the variables which were added,
+// changed and removed between two environments
+func printEnvironmentDiff(out io.Writer, previous, current []string) {
+	previousSet := map[string]string{}
+	for _, v := range previous {
+		previousSet[envKey(v)] = v
+	}
+	currentSet := map[string]string{}
+	for _, v := range current {
+		currentSet[envKey(v)] = v
+	}
+
+	var added, changed, removed []string
+	for key, v := range currentSet {
+		old, ok := previousSet[key]
+		switch {
+		case !ok:
+			added = append(added, key)
+		case old != v:
+			changed = append(changed, key)
+		}
+	}
+	for key := range previousSet {
+		if _, ok := currentSet[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	for _, keys := range []struct {
+		prefix string
+		keys   []string
+	}{{"+", added}, {"~", changed}, {"-", removed}} {
+		sort.Strings(keys.keys)
+		for _, key := range keys.keys {
+			fmt.Fprintf(out, "%s %s\n", keys.prefix, key)
+		}
+	}
 }
 
 func getUpdatedSecrets(apiClient client.SecretAPIClient, flags *pflag.FlagSet, secrets []*swarm.SecretReference) ([]*swarm.SecretReference, error) {
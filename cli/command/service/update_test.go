@@ -1,6 +1,9 @@
 package service
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"sort"
 	"testing"
@@ -494,3 +497,84 @@ func TestUpdateStopSignal(t *testing.T) {
 	updateService(nil, nil, flags, spec)
 	assert.Equal(t, "SIGWINCH", cspec.StopSignal)
 }
+
+func writeEnvFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "envfile")
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	return file.Name()
+}
+
+func TestUpdateEnvironmentFromFiles(t *testing.T) {
+	toAdd := writeEnvFile(t, "A=1\nB=2\n")
+	defer os.Remove(toAdd)
+	toRemove := writeEnvFile(t, "C=whatever\n")
+	defer os.Remove(toRemove)
+
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("env-file-add", toAdd)
+	flags.Set("env-file-rm", toRemove)
+	flags.Set("env-add", "B=3")
+
+	envs := []string{"A=0", "C=value", "D=value"}
+
+	require.NoError(t, updateEnvironment(flags, &envs))
+	sort.Strings(envs)
+	assert.Equal(t, []string{"A=1", "B=3", "D=value"}, envs)
+}
+
+func TestUpdateEnvironmentSync(t *testing.T) {
+	file := writeEnvFile(t, "A=1\nB=2\n")
+	defer os.Remove(file)
+
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("env-sync", file)
+
+	envs := []string{"A=0", "C=value"}
+
+	require.NoError(t, updateEnvironment(flags, &envs))
+	assert.Equal(t, []string{"A=1", "B=2"}, envs)
+
+	flags.Set("env-add", "D=4")
+	assert.EqualError(t, updateEnvironment(flags, &envs), "--env-sync cannot be combined with other flags changing the environment")
+}
+
+func TestUpdateEnvironmentMissingFile(t *testing.T) {
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("env-file-add", "/no/such/envfile")
+
+	envs := []string{}
+	assert.Error(t, updateEnvironment(flags, &envs))
+}
+
+func TestUpdateSpecFileEnvironment(t *testing.T) {
+	specFile := writeEnvFile(t, `{"TaskTemplate": {"ContainerSpec": {"Image": "nginx", "Env": ["A=1", "B=2"]}}}`)
+	defer os.Remove(specFile)
+
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set(flagSpecFile, specFile)
+	flags.Set("env-add", "C=3")
+
+	spec := &swarm.ServiceSpec{
+		Annotations:  swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{ContainerSpec: swarm.ContainerSpec{Env: []string{"A=0", "D=4"}}},
+	}
+	spec, previousEnv, err := updateSpec(context.Background(), nil, nil, flags, specFile, spec)
+	require.NoError(t, err)
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, []string{"A=0", "D=4"}, previousEnv)
+	sort.Strings(spec.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, []string{"A=1", "B=2", "C=3"}, spec.TaskTemplate.ContainerSpec.Env)
+
+	out := new(bytes.Buffer)
+	printEnvironmentDiff(out, previousEnv, spec.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, "+ B\n+ C\n~ A\n- D\n", out.String())
+}
+
+func TestPrintEnvironmentDiff(t *testing.T) {
+	out := new(bytes.Buffer)
+	printEnvironmentDiff(out, []string{"A=0", "B=2", "C=value"}, []string{"A=1", "B=2", "D=4", "E=5"})
+	assert.Equal(t, "+ D\n+ E\n~ A\n- C\n", out.String())
+}
@@ -1,6 +1,7 @@
 package stack
 
 import (
+	"io"
 	"strings"
 
 	"github.com/docker/cli/cli/compose/convert"
@@ -28,6 +29,7 @@ type fakeClient struct {
 	serviceRemoveFunc func(serviceID string) error
 	networkRemoveFunc func(networkID string) error
 	secretRemoveFunc  func(secretID string) error
+	serviceLogsFunc   func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 }
 
 func (cli *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
@@ -102,6 +104,14 @@ func (cli *fakeClient) SecretRemove(ctx context.Context, secretID string) error
 	return nil
 }
 
+func (cli *fakeClient) ServiceLogs(ctx context.Context, serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	return cli.serviceLogsFunc(serviceID, options)
+}
+
+func (cli *fakeClient) TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+	return swarm.Task{ID: taskID, Slot: 1}, nil, nil
+}
+
 func serviceFromName(name string) swarm.Service {
 	return swarm.Service{
 		ID: "ID-" + name,
@@ -19,6 +19,7 @@ func NewStackCommand(dockerCli *command.DockerCli) *cobra.Command {
 	cmd.AddCommand(
 		newDeployCommand(dockerCli),
 		newListCommand(dockerCli),
+		newLogsCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newServicesCommand(dockerCli),
 		newPsCommand(dockerCli),
@@ -0,0 +1,154 @@
+package stack
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// servicePollInterval is how often the services of a stack are listed when
+// following its logs, to pick up services added by a redeploy.
+var servicePollInterval = 5 * time.Second
+
+type logsOptions struct {
+	service.LogsOptions
+
+	filter    opts.FilterOpt
+	namespace string
+}
+
+func newLogsCommand(dockerCli command.Cli) *cobra.Command {
+	options := logsOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "logs [OPTIONS] STACK",
+		Short: "Fetch the logs of the services in a stack",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.namespace = args[0]
+			return runLogs(dockerCli, options)
+		},
+		Tags: map[string]string{"version": "1.29"},
+	}
+	flags := cmd.Flags()
+	service.AddLogsFlags(flags, &options.LogsOptions)
+	flags.Var(&options.filter, "filter", "Filter services based on conditions provided")
+
+	return cmd
+}
+
+func runLogs(dockerCli command.Cli, options logsOptions) error {
+	client := dockerCli.Client()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listServices := func() ([]swarm.Service, error) {
+		filter := getStackFilterFromOpt(options.namespace, options.filter)
+		return client.ServiceList(ctx, types.ServiceListOptions{Filters: filter})
+	}
+
+	services, err := listServices()
+	if err != nil {
+		return err
+	}
+
+	// if no services in this stack, print message and exit 0
+	if len(services) == 0 {
+		fmt.Fprintf(dockerCli.Out(), "Nothing found in stack: %s\n", options.namespace)
+		return nil
+	}
+
+	// the streams of all services share the output, so lines are written
+	// under a single lock to keep them from interleaving
+	mu := &sync.Mutex{}
+	stdout := &lockedWriter{mu: mu, w: dockerCli.Out()}
+	stderr := &lockedWriter{mu: mu, w: dockerCli.Err()}
+
+	type result struct {
+		service swarm.Service
+		err     error
+	}
+	results := make(chan result)
+	streaming := make(map[string]bool)
+	running := 0
+
+	start := func(s swarm.Service, logsOptions service.LogsOptions) {
+		streaming[s.ID] = true
+		running++
+		go func() {
+			err := service.WriteServiceLogs(ctx, client, s, &logsOptions, stdout, stderr)
+			select {
+			case results <- result{service: s, err: err}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	for _, s := range services {
+		start(s, options.LogsOptions)
+	}
+
+	var poll <-chan time.Time
+	if options.Follow {
+		ticker := time.NewTicker(servicePollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case res := <-results:
+			running--
+			if res.err != nil {
+				if !options.Follow {
+					return res.err
+				}
+				// keep following the other services
+				fmt.Fprintf(stderr, "error reading logs of service %s: %v\n", res.service.Spec.Name, res.err)
+			}
+			// with --until set, all streams end once it is reached
+			if running == 0 && (!options.Follow || options.Until != "") {
+				return nil
+			}
+		case <-poll:
+			services, err := listServices()
+			if err != nil {
+				return err
+			}
+			for _, s := range services {
+				if streaming[s.ID] {
+					continue
+				}
+				// show everything the service logged since it was
+				// created, rather than only the end of its logs
+				logsOptions := options.LogsOptions
+				if logsOptions.Since == "" {
+					logsOptions.Since = s.CreatedAt.Format(time.RFC3339Nano)
+				}
+				logsOptions.Tail = "all"
+				start(s, logsOptions)
+			}
+		}
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
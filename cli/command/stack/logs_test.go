@@ -0,0 +1,56 @@
+package stack
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logStream(t *testing.T, lines ...string) io.ReadCloser {
+	buf := new(bytes.Buffer)
+	stdout := stdcopy.NewStdWriter(buf, stdcopy.Stdout)
+	for _, line := range lines {
+		_, err := stdout.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	return ioutil.NopCloser(buf)
+}
+
+func TestStackLogs(t *testing.T) {
+	cli := &fakeClient{
+		services: []string{
+			objectName("foo", "web"),
+			objectName("foo", "db"),
+			objectName("bar", "web"),
+		},
+		serviceLogsFunc: func(serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+			context := "com.docker.swarm.node.id=node1,com.docker.swarm.service.id=" + serviceID + ",com.docker.swarm.task.id=task1"
+			return logStream(t, context+" hello from "+serviceID+"\n"), nil
+		},
+	}
+	buf := new(bytes.Buffer)
+	cmd := newLogsCommand(test.NewFakeCli(cli, buf))
+	cmd.SetArgs([]string{"--no-resolve", "--no-task-ids", "foo"})
+
+	require.NoError(t, cmd.Execute())
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	assert.Contains(t, buf.String(), "ID-foo_web.1@node1    | hello from ID-foo_web\n")
+	assert.Contains(t, buf.String(), "ID-foo_db.1@node1    | hello from ID-foo_db\n")
+}
+
+func TestStackLogsEmptyStack(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := newLogsCommand(test.NewFakeCli(&fakeClient{}, buf))
+	cmd.SetArgs([]string{"foo"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Nothing found in stack: foo\n", buf.String())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 )
 
@@ -40,6 +41,7 @@ func Print(ctx context.Context, dockerCli command.Cli, tasks []swarm.Task, resol
 
 	names := map[string]string{}
 	nodes := map[string]string{}
+	networks := map[string]string{}
 
 	tasksCtx := formatter.Context{
 		Output: dockerCli.Out(),
@@ -78,7 +80,18 @@ func Print(ctx context.Context, dockerCli command.Cli, tasks []swarm.Task, resol
 			names[task.ID] = indentedName
 		}
 		nodes[task.ID] = nodeValue
+
+		for _, network := range task.Spec.Networks {
+			if _, ok := networks[network.Target]; ok {
+				continue
+			}
+			networkName, err := resolver.Resolve(ctx, types.NetworkResource{}, network.Target)
+			if err != nil {
+				return err
+			}
+			networks[network.Target] = networkName
+		}
 	}
 
-	return formatter.TaskWrite(tasksCtx, tasks, names, nodes)
+	return formatter.TaskWrite(tasksCtx, tasks, names, nodes, networks)
 }
@@ -0,0 +1,19 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUntil(t *testing.T) {
+	until, err := ParseUntil("")
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+
+	until, err = ParseUntil("2017-06-01T10:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC).Unix(), until.Unix())
+}
@@ -249,6 +249,12 @@ func transformHook(
 	return data, nil
 }
 
+// ConvertToStringKeys converts the mappings produced by the YAML decoder to
+// string-keyed maps, so that they can be validated or encoded as JSON.
+func ConvertToStringKeys(value interface{}) (interface{}, error) {
+	return convertToStringKeysRecursive(value, "")
+}
+
 // keys needs to be converted to strings for jsonschema
 func convertToStringKeysRecursive(value interface{}, keyPrefix string) (interface{}, error) {
 	if mapping, ok := value.(map[interface{}]interface{}); ok {
@@ -273,7 +279,7 @@ func convertToStringKeysRecursive(value interface{}, keyPrefix string) (interfac
 		return dict, nil
 	}
 	if list, ok := value.([]interface{}); ok {
-		var convertedList []interface{}
+		convertedList := make([]interface{}, 0, len(list))
 		for index, entry := range list {
 			newKeyPrefix := fmt.Sprintf("%s[%d]", keyPrefix, index)
 			convertedEntry, err := convertToStringKeysRecursive(entry, newKeyPrefix)
@@ -0,0 +1,326 @@
+package opts
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var dotEnvKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ParseDotEnvFile reads a file of environment variables following the rules
+// of the dotenv files shared with other tools, rather than the plain
+// KEY=VALUE lines of ParseEnvFile:
+//
+//   - lines may start with "export "
+//   - values may be single or double quoted, and span several lines
+//   - double quoted values support the \n, \r, \t, \", \\ and \$ escapes
+//   - a backslash at the end of an unquoted value continues it on the next line
+//   - "#" starts a comment at the beginning of a line, after a quoted value,
+//     or after a space in an unquoted value
+//   - $VAR and ${VAR} in unquoted and double quoted values are replaced with
+//     the value of an earlier variable of the file, or of the environment
+//   - a variable name alone takes the value of the environment
+//
+// Errors report the line they occur at.
+func ParseDotEnvFile(filename string) ([]string, error) {
+	return parseDotEnvFile("env", filename, map[string]string{})
+}
+
+// ReadKVDotEnvStrings is like ReadKVStrings of the runconfig options, but
+// reads the files with ParseDotEnvFile. The variables of a file may refer to
+// the ones of the files before it. The kind of the files, such as "env" or
+// "label", is used in the errors.
+func ReadKVDotEnvStrings(kind string, files []string, override []string) ([]string, error) {
+	variables := []string{}
+	vars := map[string]string{}
+	for _, filename := range files {
+		parsed, err := parseDotEnvFile(kind, filename, vars)
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, parsed...)
+	}
+	return append(variables, override...), nil
+}
+
+func parseDotEnvFile(kind, filename string, vars map[string]string) ([]string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := parseDotEnv(content, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%s file %s: %v", kind, filename, err)
+	}
+	return variables, nil
+}
+
+// dotEnvError is an error of a dotenv file at a line
+type dotEnvError struct {
+	line int
+	msg  string
+}
+
+func (e dotEnvError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// parseDotEnv parses the content of a dotenv file into KEY=VALUE strings,
+// and records the variables in vars.
+func parseDotEnv(content []byte, vars map[string]string) ([]string, error) {
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+	for i, line := range bytes.Split(content, []byte("\n")) {
+		if !utf8.Valid(line) {
+			return nil, dotEnvError{line: i + 1, msg: "invalid utf8 bytes"}
+		}
+	}
+
+	p := &dotEnvParser{
+		src:  strings.Replace(string(content), "\r\n", "\n", -1),
+		line: 1,
+		vars: vars,
+	}
+	variables := []string{}
+	for {
+		p.skip(" \t\n")
+		if p.eof() {
+			return variables, nil
+		}
+		if p.peek() == '#' {
+			p.skipComment()
+			continue
+		}
+		key, value, err := p.parseAssignment()
+		if err != nil {
+			return nil, err
+		}
+		vars[key] = value
+		variables = append(variables, key+"="+value)
+	}
+}
+
+type dotEnvParser struct {
+	src  string
+	pos  int
+	line int
+	vars map[string]string
+}
+
+func (p *dotEnvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotEnvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+// next consumes a byte, counting the lines
+func (p *dotEnvParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *dotEnvParser) skip(chars string) {
+	for !p.eof() && strings.IndexByte(chars, p.peek()) >= 0 {
+		p.next()
+	}
+}
+
+func (p *dotEnvParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+}
+
+func (p *dotEnvParser) errorf(format string, args ...interface{}) error {
+	return dotEnvError{line: p.line, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *dotEnvParser) parseAssignment() (string, string, error) {
+	if strings.HasPrefix(p.src[p.pos:], "export ") || strings.HasPrefix(p.src[p.pos:], "export\t") {
+		p.pos += len("export")
+		p.skip(" \t")
+	}
+
+	start := p.pos
+	for !p.eof() && strings.IndexByte("= \t\n", p.peek()) < 0 {
+		p.next()
+	}
+	key := p.src[start:p.pos]
+	if !dotEnvKeyRegexp.MatchString(key) {
+		return "", "", p.errorf("invalid variable name %q", key)
+	}
+	p.skip(" \t")
+	if p.eof() || p.peek() == '\n' || p.peek() == '#' {
+		// a name alone passes the variable through from the environment,
+		// as in the env files of ParseEnvFile
+		p.skipComment()
+		return key, os.Getenv(key), nil
+	}
+	if p.peek() != '=' {
+		return "", "", p.errorf("missing '=' after variable name %q", key)
+	}
+	p.next()
+	p.skip(" \t")
+
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case p.eof():
+		return key, "", nil
+	case p.peek() == '\'':
+		value, err = p.parseSingleQuoted()
+	case p.peek() == '"':
+		value, err = p.parseDoubleQuoted()
+	default:
+		value, err = p.parseUnquoted()
+		return key, value, err
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, p.endQuotedValue()
+}
+
+// parseUnquoted reads a value up to the end of its line, or of the next one
+// if the line ends with a backslash. Trailing spaces and comments are left
+// out.
+func (p *dotEnvParser) parseUnquoted() (string, error) {
+	var value bytes.Buffer
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '\n':
+			return strings.TrimRight(value.String(), " \t"), nil
+		case c == '#' && (value.Len() == 0 || strings.IndexByte(" \t", value.Bytes()[value.Len()-1]) >= 0):
+			p.skipComment()
+			return strings.TrimRight(value.String(), " \t"), nil
+		case c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '\n':
+			p.next()
+			p.next()
+		case c == '$':
+			expanded, err := p.parseReference()
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+		default:
+			value.WriteByte(p.next())
+		}
+	}
+	return strings.TrimRight(value.String(), " \t"), nil
+}
+
+func (p *dotEnvParser) parseSingleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end < 0 {
+		return "", dotEnvError{line: startLine, msg: "unterminated single-quoted value"}
+	}
+	start := p.pos
+	for p.pos < start+end {
+		p.next()
+	}
+	p.next()
+	return p.src[start : start+end], nil
+}
+
+var dotEnvEscapes = map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', '"': '"', '\\': '\\', '$': '$'}
+
+func (p *dotEnvParser) parseDoubleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+	var value bytes.Buffer
+	for !p.eof() {
+		switch c := p.peek(); c {
+		case '"':
+			p.next()
+			return value.String(), nil
+		case '\\':
+			p.next()
+			if p.eof() {
+				break
+			}
+			if escaped, ok := dotEnvEscapes[p.peek()]; ok {
+				p.next()
+				value.WriteByte(escaped)
+			} else {
+				value.WriteByte('\\')
+			}
+		case '$':
+			expanded, err := p.parseReference()
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+		default:
+			value.WriteByte(p.next())
+		}
+	}
+	return "", dotEnvError{line: startLine, msg: "unterminated double-quoted value"}
+}
+
+// endQuotedValue checks that only spaces and a comment follow a quoted value
+func (p *dotEnvParser) endQuotedValue() error {
+	p.skip(" \t")
+	if p.eof() || p.peek() == '\n' {
+		return nil
+	}
+	if p.peek() == '#' {
+		p.skipComment()
+		return nil
+	}
+	return p.errorf("unexpected character %q after quoted value", p.peek())
+}
+
+// parseReference reads a $VAR or ${VAR} reference and returns its value. A
+// $ which does not start a reference is kept as is.
+func (p *dotEnvParser) parseReference() (string, error) {
+	p.next()
+	if !p.eof() && p.peek() == '{' {
+		end := strings.IndexAny(p.src[p.pos:], "}\n")
+		if end < 0 || p.src[p.pos+end] != '}' {
+			return "", p.errorf("unterminated variable reference")
+		}
+		name := p.src[p.pos+1 : p.pos+end]
+		if !dotEnvKeyRegexp.MatchString(name) {
+			return "", p.errorf("invalid variable name %q in reference", name)
+		}
+		p.pos += end + 1
+		return p.lookup(name), nil
+	}
+
+	start := p.pos
+	for !p.eof() && isDotEnvNameChar(p.peek(), p.pos == start) {
+		p.next()
+	}
+	if p.pos == start {
+		return "$", nil
+	}
+	return p.lookup(p.src[start:p.pos]), nil
+}
+
+func isDotEnvNameChar(c byte, first bool) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || !first && '0' <= c && c <= '9'
+}
+
+// lookup returns the value of an earlier variable of the file, or else of
+// the environment
+func (p *dotEnvParser) lookup(name string) string {
+	if value, ok := p.vars[name]; ok {
+		return value
+	}
+	return os.Getenv(name)
+}
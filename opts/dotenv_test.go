@@ -0,0 +1,101 @@
+package opts
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	os.Setenv("DOTENV_TEST_HOME", "/home/test")
+	defer os.Unsetenv("DOTENV_TEST_HOME")
+
+	content := `# comment
+PLAIN=value
+export EXPORTED=yes
+  SPACED = some value   # trailing comment
+HASH=a#b
+EMPTY=
+SINGLE='literal $PLAIN \n # kept'
+DOUBLE="tab\there \"quoted\" \$PLAIN"
+MULTI="first
+second"
+CONTINUED=one \
+two
+REF=${PLAIN}-$EXPORTED-${UNDEFINED_DOTENV_VAR}
+ENVREF=$DOTENV_TEST_HOME/bin
+DOLLAR=5$ and $1
+DOTENV_TEST_HOME
+UNDEFINED_DOTENV_VAR # not set
+`
+	variables, err := parseDotEnv([]byte(content), map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"PLAIN=value",
+		"EXPORTED=yes",
+		"SPACED=some value",
+		"HASH=a#b",
+		"EMPTY=",
+		`SINGLE=literal $PLAIN \n # kept`,
+		"DOUBLE=tab\there \"quoted\" $PLAIN",
+		"MULTI=first\nsecond",
+		"CONTINUED=one two",
+		"REF=value-yes-",
+		"ENVREF=/home/test/bin",
+		"DOLLAR=5$ and $1",
+		"DOTENV_TEST_HOME=/home/test",
+		"UNDEFINED_DOTENV_VAR=",
+	}, variables)
+}
+
+func TestParseDotEnvCRLFAndBOM(t *testing.T) {
+	variables, err := parseDotEnv([]byte("\xEF\xBB\xBFA=1\r\nB=\"2\"\r\n"), map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A=1", "B=2"}, variables)
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{content: "A=1\nNO_EQUALS value\n", expected: `line 2: missing '=' after variable name "NO_EQUALS"`},
+		{content: "A=1\n\n1BAD=x\n", expected: `line 3: invalid variable name "1BAD"`},
+		{content: "A=1\nB=\"open\nstill open\n", expected: "line 2: unterminated double-quoted value"},
+		{content: "A='open\n", expected: "line 1: unterminated single-quoted value"},
+		{content: "A=\"x\" y\n", expected: `line 1: unexpected character 'y' after quoted value`},
+		{content: "A=1\nB=${A\n", expected: "line 2: unterminated variable reference"},
+		{content: "A=\xff\n", expected: "line 1: invalid utf8 bytes"},
+	}
+	for _, tc := range testCases {
+		_, err := parseDotEnv([]byte(tc.content), map[string]string{})
+		assert.EqualError(t, err, tc.expected, tc.content)
+	}
+}
+
+func TestReadKVDotEnvStrings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first := filepath.Join(dir, "first.env")
+	second := filepath.Join(dir, "second.env")
+	require.NoError(t, ioutil.WriteFile(first, []byte("NAME=app\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(second, []byte("IMAGE=${NAME}:latest\n"), 0644))
+
+	variables, err := ReadKVDotEnvStrings("env", []string{first, second}, []string{"NAME=other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NAME=app", "IMAGE=app:latest", "NAME=other"}, variables)
+
+	bad := filepath.Join(dir, "bad.env")
+	require.NoError(t, ioutil.WriteFile(bad, []byte("OK=1\nBROKEN value\n"), 0644))
+	_, err = ParseDotEnvFile(bad)
+	assert.EqualError(t, err, "env file "+bad+`: line 2: missing '=' after variable name "BROKEN"`)
+
+	_, err = ReadKVDotEnvStrings("label", []string{bad}, nil)
+	assert.EqualError(t, err, "label file "+bad+`: line 2: missing '=' after variable name "BROKEN"`)
+}